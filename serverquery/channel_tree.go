@@ -0,0 +1,277 @@
+package serverquery
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ChannelTreeNode is a channel in a channel tree.
+type ChannelTreeNode struct {
+	*ChannelListEntry
+
+	// Parent is the parent node, or nil for root channels.
+	Parent *ChannelTreeNode
+	// Children are the subchannels, in display order.
+	Children []*ChannelTreeNode
+}
+
+// channelPathEscaper escapes slashes in channel names, like "AFK \/ Away".
+var channelPathEscaper = strings.NewReplacer(`\`, `\\`, `/`, `\/`)
+
+// Path returns the slash-separated channel names from the root to the node.
+// Slashes and backslashes in names are escaped with a backslash, so the path can be passed to Find.
+func (n *ChannelTreeNode) Path() string {
+	var names []string
+	for node := n; node != nil; node = node.Parent {
+		names = append([]string{channelPathEscaper.Replace(node.Name)}, names...)
+	}
+	return strings.Join(names, "/")
+}
+
+// splitChannelPath splits a channel path into names, unescaping them.
+// Empty names, like from leading or trailing slashes, are skipped.
+func splitChannelPath(path string) []string {
+	var names []string
+	var name strings.Builder
+	escaped := false
+	for _, r := range path {
+		switch {
+		case escaped:
+			name.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '/':
+			if name.Len() != 0 {
+				names = append(names, name.String())
+			}
+			name.Reset()
+		default:
+			name.WriteRune(r)
+		}
+	}
+	if name.Len() != 0 {
+		names = append(names, name.String())
+	}
+	return names
+}
+
+// ChannelTree is the channel hierarchy of a server, in display order.
+type ChannelTree struct {
+	// Roots are the root channels, in display order.
+	Roots []*ChannelTreeNode
+
+	// nodes maps channel ids to nodes.
+	nodes map[int]*ChannelTreeNode
+}
+
+// NewChannelTree builds a channel tree from a channel list.
+// The Order of a channel is the id of the sibling it is sorted after, or 0 if it is first.
+// Channels with a missing parent are treated as root channels, channels with
+// a broken order chain are sorted after the others by id.
+func NewChannelTree(channels []*ChannelListEntry) *ChannelTree {
+	t := &ChannelTree{nodes: make(map[int]*ChannelTreeNode, len(channels))}
+	for _, ch := range channels {
+		t.nodes[ch.Id] = &ChannelTreeNode{ChannelListEntry: ch}
+	}
+
+	siblings := make(map[int][]*ChannelTreeNode)
+	for _, ch := range channels {
+		node := t.nodes[ch.Id]
+		parentID := ch.ParentId
+		if parent, ok := t.nodes[parentID]; ok && parentID != ch.Id {
+			node.Parent = parent
+		} else {
+			parentID = 0
+		}
+		siblings[parentID] = append(siblings[parentID], node)
+	}
+
+	for parentID, nodes := range siblings {
+		ordered := orderSiblings(nodes)
+		if parentID == 0 {
+			t.Roots = ordered
+		} else {
+			t.nodes[parentID].Children = ordered
+		}
+	}
+	return t
+}
+
+// orderSiblings sorts siblings by following the Order chain.
+func orderSiblings(nodes []*ChannelTreeNode) []*ChannelTreeNode {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Id < nodes[j].Id
+	})
+	after := make(map[int]*ChannelTreeNode, len(nodes))
+	for _, node := range nodes {
+		if _, ok := after[node.Order]; !ok {
+			after[node.Order] = node
+		}
+	}
+
+	ordered := make([]*ChannelTreeNode, 0, len(nodes))
+	seen := make(map[int]bool, len(nodes))
+	for node := after[0]; node != nil && !seen[node.Id]; node = after[node.Id] {
+		seen[node.Id] = true
+		ordered = append(ordered, node)
+	}
+	for _, node := range nodes {
+		if !seen[node.Id] {
+			ordered = append(ordered, node)
+		}
+	}
+	return ordered
+}
+
+// Node returns the node of a channel.
+func (t *ChannelTree) Node(channelID int) (*ChannelTreeNode, bool) {
+	node, ok := t.nodes[channelID]
+	return node, ok
+}
+
+// children returns the children of a channel, or the roots for 0.
+func (t *ChannelTree) children(channelID int) ([]*ChannelTreeNode, bool) {
+	if channelID == 0 {
+		return t.Roots, true
+	}
+	node, ok := t.nodes[channelID]
+	if !ok {
+		return nil, false
+	}
+	return node.Children, true
+}
+
+// Walk calls fn for each channel depth-first, in display order.
+// Root channels have depth 0. Walk stops if fn returns false.
+func (t *ChannelTree) Walk(fn func(node *ChannelTreeNode, depth int) bool) {
+	walkChannelNodes(t.Roots, 0, fn)
+}
+
+// walkChannelNodes walks nodes depth-first, returning false if stopped.
+func walkChannelNodes(nodes []*ChannelTreeNode, depth int, fn func(node *ChannelTreeNode, depth int) bool) bool {
+	for _, node := range nodes {
+		if !fn(node, depth) {
+			return false
+		}
+		if !walkChannelNodes(node.Children, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find looks up a channel by slash-separated path of names, like "Lobby/Gaming/CS".
+// Slashes in names must be escaped with a backslash, like "Lobby/AFK \/ Away", as returned by Path.
+// If several siblings share a name, the first in display order is used.
+func (t *ChannelTree) Find(path string) (*ChannelTreeNode, bool) {
+	return t.FindNames(splitChannelPath(path)...)
+}
+
+// FindNames looks up a channel by the unescaped names from the root to the channel.
+// If several siblings share a name, the first in display order is used.
+func (t *ChannelTree) FindNames(names ...string) (*ChannelTreeNode, bool) {
+	var node *ChannelTreeNode
+	nodes := t.Roots
+	for _, name := range names {
+		node = nil
+		for _, child := range nodes {
+			if child.Name == name {
+				node = child
+				break
+			}
+		}
+		if node == nil {
+			return nil, false
+		}
+		nodes = node.Children
+	}
+	return node, node != nil
+}
+
+// Render renders the tree as ASCII art, one channel per line.
+func (t *ChannelTree) Render() string {
+	var buf bytes.Buffer
+	for _, node := range t.Roots {
+		buf.WriteString(node.Name)
+		buf.WriteRune('\n')
+		renderChannelNodes(&buf, node.Children, "")
+	}
+	return buf.String()
+}
+
+// renderChannelNodes renders subchannels with the line prefix.
+func renderChannelNodes(buf *bytes.Buffer, nodes []*ChannelTreeNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "+-- ", "|   "
+		if i == len(nodes)-1 {
+			branch, indent = "`-- ", "    "
+		}
+		buf.WriteString(prefix)
+		buf.WriteString(branch)
+		buf.WriteString(node.Name)
+		buf.WriteRune('\n')
+		renderChannelNodes(buf, node.Children, prefix+indent)
+	}
+}
+
+// PlanOrder computes the channelmove commands needed to place the channels
+// below parentID (0 for the root) in the desired order.
+// Channels may currently be below another parent. Existing children that
+// are not listed keep their relative order after the listed channels.
+// The tree itself is not modified.
+func (t *ChannelTree) PlanOrder(parentID int, desired []int) ([]*MoveChannelCommand, error) {
+	current, ok := t.children(parentID)
+	if !ok {
+		return nil, errors.Errorf("unknown parent channel %d", parentID)
+	}
+
+	siblings := make([]int, len(current))
+	for i, node := range current {
+		siblings[i] = node.Id
+	}
+
+	moves := make([]*MoveChannelCommand, 0)
+	for i, channelID := range desired {
+		if _, ok := t.nodes[channelID]; !ok {
+			return nil, errors.Errorf("unknown channel %d", channelID)
+		}
+		for p := t.nodes[parentID]; p != nil; p = p.Parent {
+			if p.Id == channelID {
+				return nil, errors.Errorf("cannot move channel %d below itself", channelID)
+			}
+		}
+
+		idx := -1
+		for j, id := range siblings {
+			if id == channelID {
+				idx = j
+				break
+			}
+		}
+		if idx == i {
+			continue
+		}
+		if idx < i && idx != -1 {
+			return nil, errors.Errorf("channel %d listed more than once", channelID)
+		}
+		if idx != -1 {
+			siblings = append(siblings[:idx], siblings[idx+1:]...)
+		}
+		siblings = append(siblings[:i], append([]int{channelID}, siblings[i:]...)...)
+
+		var order int
+		if i != 0 {
+			order = desired[i-1]
+		}
+		moves = append(moves, &MoveChannelCommand{
+			Id:       channelID,
+			ParentId: parentID,
+			Order:    order,
+		})
+	}
+	return moves, nil
+}
@@ -0,0 +1,137 @@
+package serverquery
+
+import (
+	"testing"
+)
+
+// buildTestChannelTree builds a channel tree from a list with shuffled entries.
+func buildTestChannelTree(t *testing.T) *ChannelTree {
+	t.Helper()
+	res, err := UnmarshalArguments(
+		"cid=4 pid=2 channel_order=0 channel_name=CS|"+
+			"cid=2 pid=1 channel_order=0 channel_name=Gaming|"+
+			"cid=5 pid=2 channel_order=4 channel_name=Chess|"+
+			"cid=1 pid=0 channel_order=0 channel_name=Lobby|"+
+			"cid=6 pid=0 channel_order=3 channel_name=AFK|"+
+			"cid=3 pid=0 channel_order=1 channel_name=Music",
+		make([]*ChannelListEntry, 0),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return NewChannelTree(res.([]*ChannelListEntry))
+}
+
+// TestChannelTree tests building, walking, finding and rendering the tree.
+func TestChannelTree(t *testing.T) {
+	tree := buildTestChannelTree(t)
+
+	var names []string
+	tree.Walk(func(node *ChannelTreeNode, depth int) bool {
+		names = append(names, node.Name)
+		return true
+	})
+	expected := []string{"Lobby", "Gaming", "CS", "Chess", "Music", "AFK"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, names)
+		}
+	}
+
+	node, ok := tree.Find("Lobby/Gaming/CS")
+	if !ok || node.Id != 4 {
+		t.Fatalf("expected to find CS, got %#v", node)
+	}
+	if node.Path() != "Lobby/Gaming/CS" {
+		t.Fatalf("unexpected path %q", node.Path())
+	}
+	if _, ok := tree.Find("Lobby/CS"); ok {
+		t.Fatal("expected Lobby/CS not to be found")
+	}
+
+	expectedRender := "Lobby\n" +
+		"`-- Gaming\n" +
+		"    +-- CS\n" +
+		"    `-- Chess\n" +
+		"Music\n" +
+		"AFK\n"
+	if r := tree.Render(); r != expectedRender {
+		t.Fatalf("unexpected render:\n%s", r)
+	}
+}
+
+// TestChannelTreePlanOrder tests computing the channelmove arguments for an ordering.
+func TestChannelTreePlanOrder(t *testing.T) {
+	tree := buildTestChannelTree(t)
+
+	moves, err := tree.PlanOrder(0, []int{1, 3, 6})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(moves) != 0 {
+		t.Fatalf("expected no moves for the current order, got %d", len(moves))
+	}
+
+	// AFK first, then Lobby, Chess pulled up from Gaming, then Music
+	moves, err = tree.PlanOrder(0, []int{6, 1, 5})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []string{
+		"channelmove cid=6 cpid=0 order=0",
+		"channelmove cid=5 cpid=0 order=1",
+	}
+	if len(moves) != len(expected) {
+		t.Fatalf("expected %d moves, got %d", len(expected), len(moves))
+	}
+	for i, move := range moves {
+		mc, err := MarshalCommand(move)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if mc != expected[i] {
+			t.Fatalf("expected %q, got %q", expected[i], mc)
+		}
+	}
+
+	if _, err := tree.PlanOrder(2, []int{1}); err == nil {
+		t.Fatal("expected error moving a channel below itself")
+	}
+	if _, err := tree.PlanOrder(0, []int{42}); err == nil {
+		t.Fatal("expected error for unknown channel")
+	}
+}
+
+// TestChannelTreePathEscaping tests paths of channels with slashes in their names.
+func TestChannelTreePathEscaping(t *testing.T) {
+	res, err := UnmarshalArguments(
+		"cid=1 pid=0 channel_order=0 channel_name=Lobby|"+
+			"cid=2 pid=1 channel_order=0 channel_name=AFK\\s\\/\\sAway|"+
+			"cid=3 pid=2 channel_order=0 channel_name=C:\\\\Games",
+		make([]*ChannelListEntry, 0),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tree := NewChannelTree(res.([]*ChannelListEntry))
+
+	node, ok := tree.Find("Lobby/AFK \\/ Away/C:\\\\Games")
+	if !ok || node.Id != 3 {
+		t.Fatalf("expected to find channel 3, got %#v", node)
+	}
+	if path := node.Path(); path != "Lobby/AFK \\/ Away/C:\\\\Games" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if found, ok := tree.Find(node.Path()); !ok || found != node {
+		t.Fatal("expected Path to round-trip through Find")
+	}
+	if found, ok := tree.FindNames("Lobby", "AFK / Away"); !ok || found.Id != 2 {
+		t.Fatalf("expected to find channel 2 by names, got %#v", found)
+	}
+	if _, ok := tree.Find("Lobby/AFK / Away"); ok {
+		t.Fatal("expected an unescaped slash to separate names")
+	}
+}
@@ -3,7 +3,6 @@ package serverquery
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"net"
 	"strings"
 	"sync"
@@ -18,53 +17,100 @@ var defaultCommandTimeout = 5 * time.Second
 // ServerQueryAPI is a client that implements the API.
 type ServerQueryAPI struct {
 	*ServerQueryReadWriter
+	// Commands provides the typed command helpers.
+	Commands
 
 	// commandQueue is the queue of outgoing commands
 	commandQueue chan *pendingCommand
 	// readQueue contains incoming lines
 	readQueue chan string
-	// readError is the last read error
-	readError error
-	// eventListeners is the list of event listeners
-	eventListeners []chan<- Event
-	// eventListenersMtx is the mtx of listeners
-	eventListenersMtx sync.Mutex
+	// subscriptions is the list of event subscriptions
+	subscriptions []*Subscription
+	// subscriptionsMtx is the mtx of subscriptions
+	subscriptionsMtx sync.Mutex
+
+	// dialer re-establishes the connection in supervised mode, nil otherwise.
+	dialer Dialer
+	// reconnectConf is the reconnect configuration in supervised mode.
+	reconnectConf ReconnectConfig
+	// session records the session setup to replay after reconnecting.
+	session sessionState
+	// connMtx guards ServerQueryReadWriter and closed.
+	connMtx sync.Mutex
+	// closed is set when Close has been called.
+	closed bool
+	// rateLimiter paces outgoing commands.
+	rateLimiter *rateLimiter
+	// keepAlive holds the keepalive configuration and latency.
+	keepAlive keepAliveState
 }
 
+// _ is a type assertion
+var _ CommandExecutor = ((*ServerQueryAPI)(nil))
+
 // NewServerQueryAPI builds a new ServerQueryAPI client.
 func NewServerQueryAPI(rw *ServerQueryReadWriter) *ServerQueryAPI {
-	return &ServerQueryAPI{
+	a := &ServerQueryAPI{
 		ServerQueryReadWriter: rw,
 		commandQueue:          make(chan *pendingCommand, 10),
-		readQueue:             make(chan string, 10),
+		rateLimiter:           newRateLimiter(&DefaultRateLimitConfig),
 	}
+	a.Commands.CommandExecutor = a
+	a.keepAlive.changed = make(chan struct{}, 1)
+	return a
 }
 
-// waitForServerIntro waits for the two introduction lines from the server.
-func (a *ServerQueryAPI) waitForServerIntro() error {
-	ignoreLines := 2
-	for i := 0; i < ignoreLines; i++ {
-		if _, err := a.ReadCommand(); err != nil {
-			return err
-		}
+// Dial attempts to dial the telnet API.
+func Dial(endp string) (*ServerQueryAPI, error) {
+	rw, err := dialReadWriter(context.Background(), endp)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	return NewServerQueryAPI(rw), nil
 }
 
-// Dial attempts to dial the telnet API.
-func Dial(endp string) (*ServerQueryAPI, error) {
-	conn, err := net.Dial("tcp", endp)
+// dialReadWriter dials the telnet API and waits for the server intro.
+func dialReadWriter(ctx context.Context, endp string) (*ServerQueryReadWriter, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", endp)
 	if err != nil {
 		return nil, err
 	}
 
-	api := NewServerQueryAPI(NewServerQueryReadWriter(conn))
-	if err := api.waitForServerIntro(); err != nil {
+	stop := interruptOnDone(ctx, conn)
+	rw := NewServerQueryReadWriter(conn)
+	err = rw.waitForServerIntro()
+	if stopErr := stop(); stopErr != nil {
+		err = stopErr
+	}
+	if err != nil {
 		conn.Close()
 		return nil, err
 	}
 
-	return api, nil
+	return rw, nil
+}
+
+// interruptOnDone interrupts blocking I/O on conn when ctx is done.
+// The returned stop func stops watching ctx, returning the ctx error if conn was interrupted.
+func interruptOnDone(ctx context.Context, conn net.Conn) (stop func() error) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	return func() error {
+		close(done)
+		<-exited
+		return ctx.Err()
+	}
 }
 
 // pendingCommand is a queued command waiting for a response.
@@ -111,21 +157,27 @@ func (a *ServerQueryAPI) ExecuteCommand(
 }
 
 // readPump reads messages from the connection.
-func (a *ServerQueryAPI) readPump(ctx context.Context) (rerr error) {
-	defer close(a.readQueue)
-	defer func() { a.readError = rerr }()
+// readError is set before readQueue is closed.
+func (a *ServerQueryAPI) readPump(
+	ctx context.Context,
+	rw *ServerQueryReadWriter,
+	readQueue chan<- string,
+	readError *error,
+) (rerr error) {
+	defer close(readQueue)
+	defer func() { *readError = rerr }()
 	for {
 		select {
 		case <-ctx.Done():
 			return context.Canceled
 		default:
 		}
-		cmd, err := a.ReadCommand()
+		cmd, err := rw.ReadCommand()
 		if err != nil {
-			return context.Canceled
+			return err
 		}
 		select {
-		case a.readQueue <- cmd:
+		case readQueue <- cmd:
 		case <-ctx.Done():
 			return context.Canceled
 		}
@@ -138,6 +190,10 @@ type callResult struct {
 	ErrorId int `serverquery:"id"`
 	// ErrorMessage is the message of the error.
 	ErrorMessage string `serverquery:"msg"`
+	// ExtraMessage is the additional message of the error.
+	ExtraMessage string `serverquery:"extra_msg"`
+	// FailedPermID is the id of the permission that caused the error.
+	FailedPermID int `serverquery:"failed_permid"`
 }
 
 // submitCommand writes a command to the server and waits for the reply.
@@ -159,26 +215,36 @@ func (a *ServerQueryAPI) submitCommand(ctx context.Context, cmdObj *pendingComma
 	timeoutTimer := time.After(defaultCommandTimeout)
 	for {
 		var response string
+		var ok bool
 		select {
 		case <-ctx.Done():
 			return nil, context.Canceled
-		case response = <-a.readQueue:
+		case response, ok = <-a.readQueue:
+			if !ok {
+				return nil, errors.New("connection closed while waiting for reply")
+			}
 		case <-timeoutTimer:
 			return nil, errors.New("command timed out")
 		}
 
+		// notifications can arrive at any time, even in the middle of a reply
+		if strings.HasPrefix(response, "notify") {
+			a.processEvent(ctx, response)
+			continue
+		}
+
 		if strings.HasPrefix(response, "error ") {
 			response = response[len("error "):]
 			respObj := &callResult{}
 
-			ri, err := UnmarshalArguments(response[len("error "):], respObj)
+			ri, err := UnmarshalArguments(response, respObj)
 			if err != nil {
 				return nil, err
 			}
 
 			respObj = ri.(*callResult)
-			if respObj.ErrorId != 0 {
-				return nil, errors.Wrap(errors.New(respObj.ErrorMessage), "server error")
+			if err := newServerError(respObj); err != nil {
+				return nil, err
 			}
 
 			if resultObj != nil {
@@ -195,85 +261,144 @@ func (a *ServerQueryAPI) submitCommand(ctx context.Context, cmdObj *pendingComma
 }
 
 // processEvent processes and emits an event correctly.
+// Lines that are not notifications are dropped.
 func (a *ServerQueryAPI) processEvent(ctx context.Context, event string) {
-	msgParts := strings.SplitN(event, " ", 2)
-	msgName := msgParts[0]
-	if !strings.HasPrefix(msgName, "notify") {
-		fmt.Printf("ignored message: %s\n", event)
+	if !strings.HasPrefix(event, "notify") {
 		return
 	}
 
-	var eve Event
-	eventName := msgName[len("notify"):]
-	c, ok := eventConstructorTable[eventName]
-	if ok {
-		proto, err := UnmarshalArguments(msgParts[1], c())
-		if err != nil {
-			return
-		}
-		eve = proto.(Event)
-	} else {
-		eve = &UnknownEvent{EventSource: event}
+	events, err := ParseEvent(event)
+	if err != nil {
+		// deliver it anyway so subscribers know the event was missed
+		events = []Event{&UnknownEvent{EventSource: event, Err: err}}
+	}
+	for _, eve := range events {
+		a.emitEvent(ctx, eve)
 	}
+}
 
-	a.eventListenersMtx.Lock()
-	for _, eventListener := range a.eventListeners {
-		select {
-		case <-ctx.Done():
+// emitEvent emits an event to the subscriptions.
+func (a *ServerQueryAPI) emitEvent(ctx context.Context, eve Event) {
+	a.subscriptionsMtx.Lock()
+	subs := make([]*Subscription, len(a.subscriptions))
+	copy(subs, a.subscriptions)
+	a.subscriptionsMtx.Unlock()
+
+	for _, sub := range subs {
+		if ctx.Err() != nil {
 			return
-		case eventListener <- eve:
-		default:
 		}
+		sub.deliver(ctx, eve)
 	}
-	a.eventListenersMtx.Unlock()
 }
 
 // Close ensures the server conn is closed down.
+// In supervised mode this also stops reconnecting.
 func (a *ServerQueryAPI) Close() {
-	a.Conn.Close()
+	a.connMtx.Lock()
+	defer a.connMtx.Unlock()
+	a.closed = true
+	if a.ServerQueryReadWriter != nil {
+		a.Conn.Close()
+	}
+}
+
+// setReadWriter swaps the active connection.
+// Returns false if the client has been closed.
+func (a *ServerQueryAPI) setReadWriter(rw *ServerQueryReadWriter) bool {
+	a.connMtx.Lock()
+	defer a.connMtx.Unlock()
+	if a.closed {
+		return false
+	}
+	a.ServerQueryReadWriter = rw
+	return true
 }
 
-// Events returns a channel of events
+// Events returns a channel of events.
+// The channel is closed when Run exits.
+// Use Subscribe to control buffering or to unsubscribe.
 func (a *ServerQueryAPI) Events() <-chan Event {
-	ch := make(chan Event, 10)
-	a.eventListenersMtx.Lock()
-	a.eventListeners = append(a.eventListeners, ch)
-	a.eventListenersMtx.Unlock()
-	return ch
+	return a.Subscribe(nil).Events()
 }
 
 // Run processes the client send/receive loop.
+// In supervised mode Run reconnects until ctx is canceled or Close is called.
 func (a *ServerQueryAPI) Run(parentContext context.Context) error {
 	ctx, ctxCancel := context.WithCancel(parentContext)
 	defer ctxCancel()
-	go a.readPump(ctx)
-	go func() {
-		a.eventListenersMtx.Lock()
-		for _, list := range a.eventListeners {
-			close(list)
+	defer a.closeSubscriptions()
+
+	if a.dialer != nil {
+		return a.runSupervised(ctx)
+	}
+	return a.runConnection(ctx, a.ServerQueryReadWriter, nil)
+}
+
+// runConnection processes the send/receive loop for a single connection.
+// setup, if set, is called once the read pump is running, before queued commands are processed.
+func (a *ServerQueryAPI) runConnection(
+	parentContext context.Context,
+	rw *ServerQueryReadWriter,
+	setup func(ctx context.Context) error,
+) error {
+	ctx, ctxCancel := context.WithCancel(parentContext)
+	defer ctxCancel()
+
+	var readError error
+	a.readQueue = make(chan string, 10)
+	go a.readPump(ctx, rw, a.readQueue, &readError)
+
+	if setup != nil {
+		if err := setup(ctx); err != nil {
+			return err
+		}
+	}
+
+	keepAliveTimer := a.newKeepAliveTimer()
+	defer func() {
+		if keepAliveTimer != nil {
+			keepAliveTimer.Stop()
 		}
-		a.eventListeners = nil
-		a.eventListenersMtx.Unlock()
 	}()
 
 	for {
+		var keepAliveCh <-chan time.Time
+		if keepAliveTimer != nil {
+			keepAliveCh = keepAliveTimer.C
+		}
+
 		select {
 		case <-ctx.Done():
 			return context.Canceled
 		case cmd := <-a.commandQueue:
-			res, err := a.submitCommand(ctx, cmd)
+			res, err := a.executePending(ctx, cmd)
 			cmd.result = res
 			select {
 			case <-ctx.Done():
 				return context.Canceled
 			case cmd.doneCh <- err:
 			}
+			if keepAliveTimer != nil {
+				keepAliveTimer.Stop()
+			}
+			keepAliveTimer = a.newKeepAliveTimer()
+		case <-keepAliveCh:
+			if err := a.sendKeepAlive(ctx); err != nil {
+				return err
+			}
+			keepAliveTimer = a.newKeepAliveTimer()
+		case <-a.keepAlive.changed:
+			if keepAliveTimer != nil {
+				keepAliveTimer.Stop()
+			}
+			keepAliveTimer = a.newKeepAliveTimer()
 		case env, ok := <-a.readQueue:
 			if !ok {
-				if a.readError == nil {
+				if readError == nil {
 					return context.Canceled
 				}
-				return a.readError
+				return readError
 			}
 			a.processEvent(ctx, env)
 		}
@@ -0,0 +1,258 @@
+package serverquery
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer is an in-memory ServerQuery server.
+type fakeServer struct {
+	conn io.ReadWriteCloser
+	// replies maps a command line to the lines written in response.
+	replies map[string][]string
+	// received contains the command lines received.
+	received chan string
+	// handler, if set, is consulted before replies.
+	handler func(line string) ([]string, bool)
+}
+
+// startFakeServer starts a fake server and returns the client end of the connection.
+func startFakeServer(t *testing.T, replies map[string][]string) (*fakeServer, net.Conn) {
+	serverConn, clientConn := net.Pipe()
+	s := serveFakeServer(serverConn, replies)
+	t.Cleanup(func() {
+		serverConn.Close()
+		clientConn.Close()
+	})
+	return s, clientConn
+}
+
+// serveFakeServer starts a fake server on an existing connection.
+func serveFakeServer(conn io.ReadWriteCloser, replies map[string][]string) *fakeServer {
+	s := &fakeServer{conn: conn, replies: replies, received: make(chan string, 100)}
+	go s.serve()
+	return s
+}
+
+// newFakeServer builds a fake server and a client connected to it.
+func newFakeServer(t *testing.T, replies map[string][]string) (*fakeServer, *ServerQueryAPI) {
+	s, clientConn := startFakeServer(t, replies)
+	return s, NewServerQueryAPI(NewServerQueryReadWriter(clientConn))
+}
+
+// expectReceived checks the next command lines received by the server.
+func (s *fakeServer) expectReceived(t *testing.T, lines ...string) {
+	t.Helper()
+	for _, expected := range lines {
+		select {
+		case line := <-s.received:
+			if line != expected {
+				t.Fatalf("expected server to receive %q, got %q", expected, line)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for server to receive %q", expected)
+		}
+	}
+}
+
+// writeLine writes a line terminated the way the real server does.
+func (s *fakeServer) writeLine(line string) error {
+	_, err := s.conn.Write([]byte(line + "\n\r"))
+	return err
+}
+
+// serve answers commands until the connection closes.
+func (s *fakeServer) serve() {
+	scanner := bufio.NewScanner(s.conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		select {
+		case s.received <- line:
+		default:
+		}
+		var lines []string
+		var ok bool
+		if s.handler != nil {
+			lines, ok = s.handler(line)
+		}
+		if !ok {
+			lines, ok = s.replies[line]
+		}
+		if !ok {
+			lines = []string{"error id=256 msg=command\\snot\\sfound"}
+		}
+		for _, line := range lines {
+			if err := s.writeLine(line); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// TestNotifyDuringReply tests notifications interleaved with command replies.
+func TestNotifyDuringReply(t *testing.T) {
+	_, api := newFakeServer(t, map[string][]string{
+		"whoami": {"error id=0 msg=ok"},
+		"clientlist -uid": {
+			"notifytextmessage targetmode=1 msg=first invokerid=3",
+			"clid=1 client_nickname=alice|clid=2 client_nickname=bob",
+			"notifytextmessage targetmode=2 msg=second invokerid=4",
+			"error id=0 msg=ok",
+		},
+		"channelinfo cid=5": {
+			"channel_name=Lobby",
+			"notifytextmessage targetmode=3 msg=third invokerid=5",
+			"channel_topic=Hello\\sthere",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	if _, err := api.ExecuteCommand(ctx, &rawCommand{name: "whoami"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	events := api.Events()
+
+	clients, err := api.GetClientList(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(clients) != 2 || clients[0].Nickname != "alice" || clients[1].Nickname != "bob" {
+		t.Fatalf("client list parsed incorrectly: %#v", clients)
+	}
+
+	channel, err := api.GetChannelInfo(ctx, 5)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if channel.Name != "Lobby" || channel.Topic != "Hello there" {
+		t.Fatalf("channel info parsed incorrectly: %#v", channel)
+	}
+
+	for _, expected := range []string{"first", "second", "third"} {
+		select {
+		case eve := <-events:
+			msg, ok := eve.(*TextMessageReceived)
+			if !ok {
+				t.Fatalf("expected text message event, got: %#v", eve)
+			}
+			if msg.Message != expected {
+				t.Fatalf("expected message %q, got %q", expected, msg.Message)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %q", expected)
+		}
+	}
+}
+
+// TestUndecodableEvent tests that a notification that cannot be decoded is delivered as an UnknownEvent.
+func TestUndecodableEvent(t *testing.T) {
+	api := NewServerQueryAPI(nil)
+	sub := Subscribe[*UnknownEvent](api)
+	line := "notifyclientmoved ctid=lots reasonid=0 clid=5"
+	api.processEvent(context.Background(), line)
+
+	select {
+	case e := <-sub.Events():
+		if e.EventSource != line || e.Err == nil {
+			t.Fatalf("undecodable event delivered incorrectly: %#v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for undecodable event")
+	}
+}
+
+// rawCommand is a command with no arguments or response.
+type rawCommand struct {
+	name string
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *rawCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *rawCommand) GetCommandName() string {
+	return c.name
+}
+
+// startStalledListener accepts connections without ever sending the server intro.
+func startStalledListener(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var conns []net.Conn
+	accepted := make(chan struct{})
+	go func() {
+		defer close(accepted)
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	t.Cleanup(func() {
+		lis.Close()
+		<-accepted
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+	return lis.Addr().String()
+}
+
+// TestDialCanceledDuringIntro tests that canceling ctx stops waiting for a stalled server.
+func TestDialCanceledDuringIntro(t *testing.T) {
+	addr := startStalledListener(t)
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, ctxCancel)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := dialReadWriter(ctx, addr)
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != context.Canceled {
+			t.Fatalf("expected context canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("dial did not return after ctx was canceled")
+	}
+}
+
+// TestReadCommandSkipsBlankLines tests that blank lines do not count as intro or reply lines.
+func TestReadCommandSkipsBlankLines(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	go io.WriteString(serverConn, "TS3\n\r\n\rWelcome\n\r\r\n\n\rerror id=0 msg=ok\n\r")
+
+	rw := NewServerQueryReadWriter(clientConn)
+	if err := rw.waitForServerIntro(); err != nil {
+		t.Fatal(err.Error())
+	}
+	line, err := rw.ReadCommand()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if line != "error id=0 msg=ok" {
+		t.Fatalf("expected the reply line after the intro, got %q", line)
+	}
+}
@@ -0,0 +1,241 @@
+package serverquery
+
+import (
+	"fmt"
+)
+
+// ServerErrorID is an error id returned by the server.
+// ServerErrorID implements error so the constants below can be used with errors.Is.
+type ServerErrorID int
+
+// Error returns the error string.
+func (i ServerErrorID) Error() string {
+	return fmt.Sprintf("server error id %d", int(i))
+}
+
+// Documented ServerQuery error ids.
+const (
+	// ErrOK indicates success.
+	ErrOK ServerErrorID = 0
+	// ErrUndefined is an undefined error.
+	ErrUndefined ServerErrorID = 1
+	// ErrNotImplemented indicates the command is not implemented.
+	ErrNotImplemented ServerErrorID = 2
+	// ErrTimeLimitReached indicates a library time limit was reached.
+	ErrTimeLimitReached ServerErrorID = 5
+
+	// ErrCommandNotFound indicates the command does not exist.
+	ErrCommandNotFound ServerErrorID = 256
+	// ErrUnableToBindPort indicates the network port could not be bound.
+	ErrUnableToBindPort ServerErrorID = 257
+	// ErrNoPortAvailable indicates no network port is available.
+	ErrNoPortAvailable ServerErrorID = 258
+
+	// ErrInvalidClientID indicates the client id is invalid.
+	ErrInvalidClientID ServerErrorID = 512
+	// ErrNicknameInUse indicates the nickname is already in use.
+	ErrNicknameInUse ServerErrorID = 513
+	// ErrInvalidErrorCode indicates the error code is invalid.
+	ErrInvalidErrorCode ServerErrorID = 514
+	// ErrClientLimitReached indicates the max clients protocol limit was reached.
+	ErrClientLimitReached ServerErrorID = 515
+	// ErrInvalidClientType indicates the client type is invalid.
+	ErrInvalidClientType ServerErrorID = 516
+	// ErrAlreadySubscribed indicates the client is already subscribed.
+	ErrAlreadySubscribed ServerErrorID = 517
+	// ErrNotLoggedIn indicates the client is not logged in.
+	ErrNotLoggedIn ServerErrorID = 518
+	// ErrCouldNotValidateIdentity indicates the client identity could not be validated.
+	ErrCouldNotValidateIdentity ServerErrorID = 519
+	// ErrInvalidLogin indicates the username or password is invalid.
+	ErrInvalidLogin ServerErrorID = 520
+	// ErrTooManyClones indicates too many clones are already connected.
+	ErrTooManyClones ServerErrorID = 521
+	// ErrClientVersionOutdated indicates the client version is outdated.
+	ErrClientVersionOutdated ServerErrorID = 522
+	// ErrClientIsOnline indicates the client is online.
+	ErrClientIsOnline ServerErrorID = 523
+	// ErrClientFlooding indicates the client is flooding.
+	ErrClientFlooding ServerErrorID = 524
+	// ErrClientHacked indicates the client is modified.
+	ErrClientHacked ServerErrorID = 525
+	// ErrCannotVerifyNow indicates the client cannot be verified now.
+	ErrCannotVerifyNow ServerErrorID = 526
+	// ErrLoginNotPermitted indicates the login is not permitted.
+	ErrLoginNotPermitted ServerErrorID = 527
+	// ErrNotSubscribed indicates the client is not subscribed.
+	ErrNotSubscribed ServerErrorID = 528
+
+	// ErrInvalidChannelID indicates the channel id is invalid.
+	ErrInvalidChannelID ServerErrorID = 768
+	// ErrChannelLimitReached indicates the max channels protocol limit was reached.
+	ErrChannelLimitReached ServerErrorID = 769
+	// ErrAlreadyMember indicates the client is already a member of the channel.
+	ErrAlreadyMember ServerErrorID = 770
+	// ErrChannelNameInUse indicates the channel name is already in use.
+	ErrChannelNameInUse ServerErrorID = 771
+	// ErrChannelNotEmpty indicates the channel is not empty.
+	ErrChannelNotEmpty ServerErrorID = 772
+	// ErrCannotDeleteDefaultChannel indicates the default channel cannot be deleted.
+	ErrCannotDeleteDefaultChannel ServerErrorID = 773
+	// ErrDefaultChannelRequiresPermanent indicates the default channel must be permanent.
+	ErrDefaultChannelRequiresPermanent ServerErrorID = 774
+	// ErrInvalidChannelFlags indicates the channel flags are invalid.
+	ErrInvalidChannelFlags ServerErrorID = 775
+	// ErrParentChannelNotPermanent indicates the parent channel is not permanent.
+	ErrParentChannelNotPermanent ServerErrorID = 776
+	// ErrChannelMaxClientsReached indicates the channel is full.
+	ErrChannelMaxClientsReached ServerErrorID = 777
+	// ErrChannelMaxFamilyReached indicates the channel family is full.
+	ErrChannelMaxFamilyReached ServerErrorID = 778
+	// ErrInvalidChannelOrder indicates the channel order is invalid.
+	ErrInvalidChannelOrder ServerErrorID = 779
+	// ErrChannelNoFileTransfer indicates the channel does not support file transfers.
+	ErrChannelNoFileTransfer ServerErrorID = 780
+	// ErrInvalidChannelPassword indicates the channel password is invalid.
+	ErrInvalidChannelPassword ServerErrorID = 781
+
+	// ErrInvalidServerID indicates the virtual server id is invalid.
+	ErrInvalidServerID ServerErrorID = 1024
+	// ErrServerRunning indicates the virtual server is running.
+	ErrServerRunning ServerErrorID = 1025
+	// ErrServerShuttingDown indicates the virtual server is shutting down.
+	ErrServerShuttingDown ServerErrorID = 1026
+	// ErrServerMaxClientsReached indicates the virtual server is full.
+	ErrServerMaxClientsReached ServerErrorID = 1027
+	// ErrInvalidServerPassword indicates the server password is invalid.
+	ErrInvalidServerPassword ServerErrorID = 1028
+	// ErrServerNotRunning indicates the virtual server is not running.
+	ErrServerNotRunning ServerErrorID = 1033
+	// ErrServerBooting indicates the virtual server is booting.
+	ErrServerBooting ServerErrorID = 1034
+
+	// ErrDatabase is a generic database error.
+	ErrDatabase ServerErrorID = 1280
+	// ErrDatabaseEmptyResult indicates the database query returned no results.
+	ErrDatabaseEmptyResult ServerErrorID = 1281
+	// ErrDatabaseDuplicateEntry indicates the database entry already exists.
+	ErrDatabaseDuplicateEntry ServerErrorID = 1282
+	// ErrDatabaseNoModifications indicates nothing was changed.
+	ErrDatabaseNoModifications ServerErrorID = 1283
+
+	// ErrInvalidParameterQuote indicates a parameter has invalid quoting.
+	ErrInvalidParameterQuote ServerErrorID = 1536
+	// ErrInvalidParameterCount indicates the parameter count is invalid.
+	ErrInvalidParameterCount ServerErrorID = 1537
+	// ErrInvalidParameter indicates a parameter is invalid.
+	ErrInvalidParameter ServerErrorID = 1538
+	// ErrParameterNotFound indicates a parameter was not found.
+	ErrParameterNotFound ServerErrorID = 1539
+	// ErrParameterConvert indicates a parameter could not be converted.
+	ErrParameterConvert ServerErrorID = 1540
+	// ErrInvalidParameterSize indicates a parameter has an invalid size.
+	ErrInvalidParameterSize ServerErrorID = 1541
+	// ErrMissingParameter indicates a required parameter is missing.
+	ErrMissingParameter ServerErrorID = 1542
+
+	// ErrFileInvalidName indicates the file name is invalid.
+	ErrFileInvalidName ServerErrorID = 2048
+	// ErrFileInvalidPermissions indicates the file permissions are invalid.
+	ErrFileInvalidPermissions ServerErrorID = 2049
+	// ErrFileAlreadyExists indicates the file already exists.
+	ErrFileAlreadyExists ServerErrorID = 2050
+	// ErrFileNotFound indicates the file was not found.
+	ErrFileNotFound ServerErrorID = 2051
+
+	// ErrInvalidGroupID indicates the group id is invalid.
+	ErrInvalidGroupID ServerErrorID = 2560
+	// ErrPermissionDuplicateEntry indicates the permission already exists.
+	ErrPermissionDuplicateEntry ServerErrorID = 2561
+	// ErrInvalidPermissionID indicates the permission id is invalid.
+	ErrInvalidPermissionID ServerErrorID = 2562
+	// ErrPermissionEmptyResult indicates no permissions were found.
+	ErrPermissionEmptyResult ServerErrorID = 2563
+	// ErrDefaultGroupForbidden indicates the default group cannot be modified.
+	ErrDefaultGroupForbidden ServerErrorID = 2564
+	// ErrInvalidPermissionSize indicates the permission size is invalid.
+	ErrInvalidPermissionSize ServerErrorID = 2565
+	// ErrInvalidPermissionValue indicates the permission value is invalid.
+	ErrInvalidPermissionValue ServerErrorID = 2566
+	// ErrGroupNotEmpty indicates the group is not empty.
+	ErrGroupNotEmpty ServerErrorID = 2567
+	// ErrInsufficientPermissions indicates the client has insufficient permissions.
+	ErrInsufficientPermissions ServerErrorID = 2568
+	// ErrInsufficientGroupPower indicates the client has insufficient group modify power.
+	ErrInsufficientGroupPower ServerErrorID = 2569
+	// ErrInsufficientPermissionPower indicates the client has insufficient permission modify power.
+	ErrInsufficientPermissionPower ServerErrorID = 2570
+
+	// ErrVirtualServerLimitReached indicates the license virtual server limit was reached.
+	ErrVirtualServerLimitReached ServerErrorID = 2816
+	// ErrSlotLimitReached indicates the license slot limit was reached.
+	ErrSlotLimitReached ServerErrorID = 2817
+
+	// ErrInvalidMessageID indicates the message id is invalid.
+	ErrInvalidMessageID ServerErrorID = 3072
+
+	// ErrInvalidBanID indicates the ban id is invalid.
+	ErrInvalidBanID ServerErrorID = 3328
+	// ErrBanned indicates the connection failed because the client is banned.
+	// The server uses this id for flood bans.
+	ErrBanned ServerErrorID = 3329
+	// ErrRenameBanned indicates the rename failed because the name is banned.
+	ErrRenameBanned ServerErrorID = 3330
+	// ErrBanFlooding indicates a ban was issued for flooding.
+	ErrBanFlooding ServerErrorID = 3331
+
+	// ErrInvalidToken indicates the privilege key is invalid.
+	ErrInvalidToken ServerErrorID = 3840
+	// ErrTokenTooManyUses indicates the privilege key has been used too often.
+	ErrTokenTooManyUses ServerErrorID = 3841
+)
+
+// ServerError is an error returned by the server in response to a command.
+type ServerError struct {
+	// ID is the error id.
+	ID ServerErrorID
+	// Message is the error message.
+	Message string
+	// ExtraMessage is the additional error message, if any.
+	ExtraMessage string
+	// FailedPermID is the id of the permission that was missing, if any.
+	FailedPermID int
+}
+
+// newServerError builds a ServerError from a call result.
+// Returns nil if the call result indicates success.
+func newServerError(res *callResult) error {
+	if ServerErrorID(res.ErrorId) == ErrOK {
+		return nil
+	}
+	return &ServerError{
+		ID:           ServerErrorID(res.ErrorId),
+		Message:      res.ErrorMessage,
+		ExtraMessage: res.ExtraMessage,
+		FailedPermID: res.FailedPermID,
+	}
+}
+
+// Error returns the error string.
+func (e *ServerError) Error() string {
+	msg := fmt.Sprintf("server error %d: %s", int(e.ID), e.Message)
+	if e.ExtraMessage != "" {
+		msg += " (" + e.ExtraMessage + ")"
+	}
+	if e.FailedPermID != 0 {
+		msg += fmt.Sprintf(" [failed_permid=%d]", e.FailedPermID)
+	}
+	return msg
+}
+
+// Is checks if the error matches the target, which may be a ServerErrorID or a *ServerError.
+func (e *ServerError) Is(target error) bool {
+	switch t := target.(type) {
+	case ServerErrorID:
+		return e.ID == t
+	case *ServerError:
+		return e.ID == t.ID
+	default:
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package serverquery
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// TestServerError tests decoding a server error line into a ServerError.
+func TestServerError(t *testing.T) {
+	res := &callResult{}
+	if _, err := UnmarshalArguments(
+		"id=2568 msg=insufficient\\sclient\\spermissions extra_msg=no\\ssir failed_permid=4",
+		res,
+	); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err := errors.Wrap(newServerError(res), "clientkick")
+	if !errors.Is(err, ErrInsufficientPermissions) {
+		t.Fatalf("expected insufficient permissions error, got: %v", err)
+	}
+	if errors.Is(err, ErrInvalidClientID) {
+		t.Fatalf("did not expect invalid client id error, got: %v", err)
+	}
+	if !errors.Is(err, &ServerError{ID: ErrInsufficientPermissions}) {
+		t.Fatalf("expected to match ServerError by id, got: %v", err)
+	}
+
+	var serr *ServerError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected ServerError, got: %#v", err)
+	}
+	if serr.Message != "insufficient client permissions" ||
+		serr.ExtraMessage != "no sir" ||
+		serr.FailedPermID != 4 {
+		t.Fatalf("server error decoded incorrectly: %#v", serr)
+	}
+}
+
+// TestServerErrorOK tests that an ok result is not an error.
+func TestServerErrorOK(t *testing.T) {
+	res := &callResult{}
+	if _, err := UnmarshalArguments("id=0 msg=ok", res); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := newServerError(res); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
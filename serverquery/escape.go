@@ -0,0 +1,44 @@
+package serverquery
+
+import (
+	"strings"
+)
+
+// escapeTable maps raw characters to their ServerQuery escape sequences.
+// strings.Replacer makes a single pass, so replaced output is never escaped again.
+var escapeTable = []string{
+	"\\", "\\\\",
+	"/", "\\/",
+	" ", "\\s",
+	"|", "\\p",
+	"\a", "\\a",
+	"\b", "\\b",
+	"\f", "\\f",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+	"\v", "\\v",
+}
+
+// escapeReplacer escapes raw strings.
+var escapeReplacer = strings.NewReplacer(escapeTable...)
+
+// unescapeReplacer reverses escapeReplacer.
+var unescapeReplacer = func() *strings.Replacer {
+	rev := make([]string, len(escapeTable))
+	for i := 0; i < len(escapeTable); i += 2 {
+		rev[i] = escapeTable[i+1]
+		rev[i+1] = escapeTable[i]
+	}
+	return strings.NewReplacer(rev...)
+}()
+
+// EscapeString escapes a string according to the ServerQuery specification.
+func EscapeString(str string) string {
+	return escapeReplacer.Replace(str)
+}
+
+// UnescapeString reverses EscapeString.
+func UnescapeString(str string) string {
+	return unescapeReplacer.Replace(str)
+}
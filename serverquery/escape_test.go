@@ -0,0 +1,66 @@
+package serverquery
+
+import (
+	"testing"
+)
+
+// TestEscapeString tests every escape in the ServerQuery specification.
+func TestEscapeString(t *testing.T) {
+	cases := []struct {
+		raw     string
+		escaped string
+	}{
+		{"back\\slash", "back\\\\slash"},
+		{"for/ward", "for\\/ward"},
+		{"white space", "white\\sspace"},
+		{"pi|pe", "pi\\ppe"},
+		{"bell\a", "bell\\a"},
+		{"back\bspace", "back\\bspace"},
+		{"form\ffeed", "form\\ffeed"},
+		{"new\nline", "new\\nline"},
+		{"carriage\rreturn", "carriage\\rreturn"},
+		{"hor\ttab", "hor\\ttab"},
+		{"vert\vtab", "vert\\vtab"},
+		{"\\s", "\\\\s"},
+		{"héllo wörld ✓", "héllo\\swörld\\s✓"},
+		{"こんにちは|世界", "こんにちは\\p世界"},
+	}
+
+	for _, c := range cases {
+		esc := EscapeString(c.raw)
+		if esc != c.escaped {
+			t.Fatalf("escape %q: expected %q, got %q", c.raw, c.escaped, esc)
+		}
+		unesc := UnescapeString(esc)
+		if unesc != c.raw {
+			t.Fatalf("unescape %q: expected %q, got %q", esc, c.raw, unesc)
+		}
+	}
+}
+
+// TestEscapeRoundTrip tests marshalling and unmarshalling escaped strings.
+func TestEscapeRoundTrip(t *testing.T) {
+	msgs := []string{
+		"a|b c/d\\e",
+		"line one\nline two\r\n\ttabbed\v\f\a\b",
+		"ünïcödé ✓ 日本語",
+		"12345",
+		"\"quoted\"",
+	}
+
+	for _, msg := range msgs {
+		cmd := &SendTextMessageCommand{TargetMode: 2, Target: 1, Message: msg}
+		str, err := MarshalArguments(cmd)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		outp := &SendTextMessageCommand{}
+		if _, err := UnmarshalArguments(str, outp); err != nil {
+			t.Fatal(err.Error())
+		}
+		if outp.Message != msg {
+			t.Fatalf("round trip of %q via %q returned %q", msg, str, outp.Message)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package serverquery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// KeepAliveConfig configures the keepalive heartbeat.
+type KeepAliveConfig struct {
+	// Interval is the idle time after which a keepalive is sent.
+	// The server disconnects idle query clients after about 5 minutes.
+	Interval time.Duration
+	// Timeout is the time to wait for the keepalive reply.
+	// If the reply does not arrive in time the connection is considered dead.
+	Timeout time.Duration
+}
+
+// DefaultKeepAliveConfig is a keepalive configuration well below the server idle timeout.
+var DefaultKeepAliveConfig = KeepAliveConfig{
+	Interval: time.Minute,
+	Timeout:  10 * time.Second,
+}
+
+// keepAliveCommand is the command sent as a keepalive.
+type keepAliveCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *keepAliveCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *keepAliveCommand) GetCommandName() string {
+	return "whoami"
+}
+
+// keepAliveState holds the keepalive configuration and measurements.
+type keepAliveState struct {
+	mtx sync.Mutex
+	// conf is the configuration, nil if disabled.
+	conf *KeepAliveConfig
+	// latency is the last measured round-trip time.
+	latency time.Duration
+	// changed signals the Run loop that conf has changed.
+	changed chan struct{}
+}
+
+// getConfig returns a copy of the configuration, nil if disabled.
+func (s *keepAliveState) getConfig() *KeepAliveConfig {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.conf == nil {
+		return nil
+	}
+	c := *s.conf
+	return &c
+}
+
+// SetKeepAlive enables sending a keepalive when no command has been sent for the configured interval.
+// Pass nil to disable it. Takes effect immediately, also while Run is idle.
+// If the keepalive fails, Run returns the error, or reconnects in supervised mode.
+func (a *ServerQueryAPI) SetKeepAlive(conf *KeepAliveConfig) {
+	a.keepAlive.mtx.Lock()
+	if conf != nil {
+		c := *conf
+		conf = &c
+	}
+	a.keepAlive.conf = conf
+	a.keepAlive.mtx.Unlock()
+
+	select {
+	case a.keepAlive.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Latency returns the round-trip time of the last keepalive, or 0 if none has completed.
+func (a *ServerQueryAPI) Latency() time.Duration {
+	a.keepAlive.mtx.Lock()
+	defer a.keepAlive.mtx.Unlock()
+	return a.keepAlive.latency
+}
+
+// newKeepAliveTimer starts a keepalive timer with the current configuration.
+// Returns nil if the keepalive is disabled.
+func (a *ServerQueryAPI) newKeepAliveTimer() *time.Timer {
+	conf := a.keepAlive.getConfig()
+	if conf == nil || conf.Interval <= 0 {
+		return nil
+	}
+	return time.NewTimer(conf.Interval)
+}
+
+// sendKeepAlive sends a keepalive and records the latency.
+func (a *ServerQueryAPI) sendKeepAlive(ctx context.Context) error {
+	conf := a.keepAlive.getConfig()
+	if conf == nil {
+		return nil
+	}
+
+	timeout := conf.Timeout
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	kctx, kctxCancel := context.WithTimeout(ctx, timeout)
+	defer kctxCancel()
+
+	mc, err := MarshalCommand(&keepAliveCommand{})
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	_, err = a.executePending(kctx, &pendingCommand{ctx: kctx, command: mc})
+	if err != nil {
+		if ctx.Err() != nil {
+			return context.Canceled
+		}
+		if kctx.Err() == context.DeadlineExceeded {
+			return errors.New("keepalive timed out")
+		}
+		var serr *ServerError
+		if !errors.As(err, &serr) {
+			return errors.Wrap(err, "keepalive failed")
+		}
+	}
+
+	a.keepAlive.mtx.Lock()
+	a.keepAlive.latency = time.Since(start)
+	a.keepAlive.mtx.Unlock()
+	return nil
+}
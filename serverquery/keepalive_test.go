@@ -0,0 +1,81 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestKeepAlive tests sending keepalives while idle.
+func TestKeepAlive(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"whoami": {"virtualserver_status=online client_id=1", "error id=0 msg=ok"},
+	})
+	api.SetKeepAlive(&KeepAliveConfig{Interval: 10 * time.Millisecond, Timeout: time.Second})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	s.expectReceived(t, "whoami", "whoami")
+	deadline := time.Now().Add(time.Second)
+	for api.Latency() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected latency to be recorded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestKeepAliveDeadConnection tests that a missing keepalive reply ends Run.
+func TestKeepAliveDeadConnection(t *testing.T) {
+	s, api := newFakeServer(t, nil)
+	s.handler = func(line string) ([]string, bool) {
+		return nil, true
+	}
+	api.SetKeepAlive(&KeepAliveConfig{Interval: 10 * time.Millisecond, Timeout: 20 * time.Millisecond})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- api.Run(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err == nil || err == context.Canceled {
+			t.Fatalf("expected keepalive error, got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return after keepalive timeout")
+	}
+}
+
+// TestKeepAliveEnabledWhileIdle tests enabling the keepalive on a running client that sends no commands.
+func TestKeepAliveEnabledWhileIdle(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"whoami": {"virtualserver_status=online client_id=1", "error id=0 msg=ok"},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	// give Run time to start its loop with the keepalive disabled
+	time.Sleep(20 * time.Millisecond)
+	api.SetKeepAlive(&KeepAliveConfig{Interval: 10 * time.Millisecond, Timeout: time.Second})
+	s.expectReceived(t, "whoami")
+
+	// a keepalive may be in flight while disabling
+	api.SetKeepAlive(nil)
+	time.Sleep(20 * time.Millisecond)
+	for len(s.received) != 0 {
+		<-s.received
+	}
+	select {
+	case line := <-s.received:
+		t.Fatalf("unexpected command after disabling keepalive: %s", line)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -15,6 +15,30 @@ type stringable interface {
 	String() string
 }
 
+// tagOptions are the options of a serverquery struct tag.
+type tagOptions struct {
+	// omitEmpty skips the field if it has the zero value.
+	omitEmpty bool
+	// pipe encodes a slice as repeated keys joined with pipes, like clid=1|clid=2.
+	// A slice of structs is encoded as groups, like permsid=a permvalue=1|permsid=b permvalue=2.
+	pipe bool
+}
+
+// parseTag splits a serverquery struct tag like "clid,pipe" into the key and options.
+func parseTag(tag string) (string, tagOptions) {
+	var opts tagOptions
+	pts := strings.Split(tag, ",")
+	for _, opt := range pts[1:] {
+		switch opt {
+		case "omitempty":
+			opts.omitEmpty = true
+		case "pipe":
+			opts.pipe = true
+		}
+	}
+	return pts[0], opts
+}
+
 // encodeArgument encodes a specific argument
 func encodeArgument(arg interface{}) (rStr string, rErr error) {
 	defer func() {
@@ -24,10 +48,7 @@ func encodeArgument(arg interface{}) (rStr string, rErr error) {
 		if len(argStr) == 0 {
 			return "", nil
 		}
-		quoted := strconv.QuoteToASCII(argStr)
-		quoted = strings.Replace(quoted, " ", "\\s", -1)
-		quoted = quoted[1 : len(quoted)-1]
-		return quoted + " ", nil
+		return EscapeString(argStr) + " ", nil
 	}
 
 	typeOfArg := reflect.TypeOf(arg)
@@ -106,6 +127,29 @@ func encodeArgument(arg interface{}) (rStr string, rErr error) {
 		if !ok {
 			continue
 		}
+		sqtag, opts := parseTag(sqtag)
+		if opts.omitEmpty && fieldVal.IsZero() {
+			continue
+		}
+		if opts.pipe && fieldVal.Kind() == reflect.Slice {
+			groups := make([]string, fieldVal.Len())
+			for j := range groups {
+				elem := fieldVal.Index(j)
+				elemStr, err := encodeArgument(elem.Interface())
+				if err != nil {
+					return "", err
+				}
+				// structs are encoded as a whole group, like permsid=a permvalue=1
+				if reflect.Indirect(elem).Kind() == reflect.Struct {
+					groups[j] = elemStr
+				} else {
+					groups[j] = sqtag + "=" + elemStr
+				}
+			}
+			res.WriteString(strings.Join(groups, "|"))
+			res.WriteRune(' ')
+			continue
+		}
 
 		res.WriteString(sqtag)
 		res.WriteRune('=')
@@ -120,6 +164,22 @@ func encodeArgument(arg interface{}) (rStr string, rErr error) {
 	return strings.TrimSpace(res.String()), nil
 }
 
+// encodeArgumentMap encodes raw key/value pairs, the inverse of splitArgumentList.
+func encodeArgumentMap(args map[string]string) string {
+	var buf bytes.Buffer
+	for key, val := range args {
+		if buf.Len() != 0 {
+			buf.WriteRune(' ')
+		}
+		buf.WriteString(key)
+		if len(val) != 0 {
+			buf.WriteRune('=')
+			buf.WriteString(EscapeString(val))
+		}
+	}
+	return buf.String()
+}
+
 // MarshalArguments converts one or more ServerQuery arguments to a string.
 func MarshalArguments(args ...interface{}) (string, error) {
 	var buf bytes.Buffer
@@ -32,7 +32,7 @@ func TestMarshalArguments(t *testing.T) {
 		panic(err)
 	}
 
-	expected := "nested=\"nested thing\" thingtype=1 thingname=\"testing 123\" thing_bool=1 thing_list=5,4,2"
+	expected := "nested=nested\\sthing thingtype=1 thingname=testing\\s123 thing_bool=1 thing_list=5,4,2"
 	if str != expected {
 		t.Fatalf("marshal returned (expected %s): %s", expected, str)
 	}
@@ -50,3 +50,100 @@ func TestMarshalCommand(t *testing.T) {
 		t.Fatalf("expected %s, got: %s", expected, str)
 	}
 }
+
+// TestMarshalTagOptions tests the omitempty and pipe tag options.
+func TestMarshalTagOptions(t *testing.T) {
+	description := ""
+	isTalker := true
+	cases := []struct {
+		cmd      Command
+		expected string
+	}{
+		{
+			&ClientKickCommand{ClientIds: []int{1, 2}, ReasonId: KickReasonServer, ReasonMessage: "bye now"},
+			"clientkick clid=1|clid=2 reasonid=5 reasonmsg=bye\\snow",
+		},
+		{
+			&ClientKickCommand{ClientIds: []int{3}, ReasonId: KickReasonChannel},
+			"clientkick clid=3 reasonid=4",
+		},
+		{
+			&ClientMoveCommand{ClientIds: []int{4, 5}, ChannelId: 7, ChannelPassword: "secret"},
+			"clientmove clid=4|clid=5 cid=7 cpw=secret",
+		},
+		{
+			&ClientEditCommand{ClientId: 6, ClientEditProperties: ClientEditProperties{
+				Description: &description,
+				IsTalker:    &isTalker,
+			}},
+			"clientedit client_description= client_is_talker=1 clid=6",
+		},
+	}
+	for _, c := range cases {
+		str, err := MarshalCommand(c.cmd)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if str != c.expected {
+			t.Fatalf("expected %s, got: %s", c.expected, str)
+		}
+	}
+}
+
+// TestMarshalServerGroupCommands tests the wire strings of the server group commands.
+func TestMarshalServerGroupCommands(t *testing.T) {
+	cases := []struct {
+		cmd      Command
+		expected string
+	}{
+		{&ServerGroupAddClientCommand{ServerGroupID: 6, ClientDBId: 12}, "servergroupaddclient sgid=6 cldbid=12"},
+		{&ServerGroupDelClientCommand{ServerGroupID: 6, ClientDBId: 12}, "servergroupdelclient sgid=6 cldbid=12"},
+		{&ServerGroupAddCommand{Name: "Game Admins", Type: GroupTypeRegular}, "servergroupadd name=Game\\sAdmins type=1"},
+		{&ServerGroupDelCommand{ServerGroupID: 9, Force: true}, "servergroupdel sgid=9 force=1"},
+		{&ServerGroupRenameCommand{ServerGroupID: 9, Name: "Mods"}, "servergrouprename sgid=9 name=Mods"},
+		{
+			&ServerGroupCopyCommand{SourceServerGroupID: 6, Name: "Copy", Type: GroupTypeRegular},
+			"servergroupcopy ssgid=6 tsgid=0 name=Copy type=1",
+		},
+		{&ServerGroupClientListCommand{ServerGroupID: 6}, "servergroupclientlist sgid=6"},
+		{&ServerGroupClientListCommand{ServerGroupID: 6, Names: true}, "servergroupclientlist -names sgid=6"},
+		{&ServerGroupsByClientIDCommand{ClientDBId: 12}, "servergroupsbyclientid cldbid=12"},
+		{
+			&ServerGroupAutoAddPermCommand{GroupType: 40, PermissionName: "i_client_talk_power", Value: 75, Skip: true},
+			"servergroupautoaddperm sgtype=40 permsid=i_client_talk_power permvalue=75 permnegated=0 permskip=1",
+		},
+	}
+	for _, c := range cases {
+		str, err := MarshalCommand(c.cmd)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if str != c.expected {
+			t.Fatalf("expected %s, got: %s", c.expected, str)
+		}
+	}
+}
+
+// TestMarshalChannelGroupCommands tests the wire strings of the channel group commands.
+func TestMarshalChannelGroupCommands(t *testing.T) {
+	cases := []struct {
+		cmd      Command
+		expected string
+	}{
+		{&ChannelGroupAddCommand{Name: "Channel Admin", Type: GroupTypeRegular}, "channelgroupadd name=Channel\\sAdmin type=1"},
+		{&ChannelGroupDelCommand{ChannelGroupID: 5}, "channelgroupdel cgid=5 force=0"},
+		{&ChannelGroupRenameCommand{ChannelGroupID: 5, Name: "Op"}, "channelgrouprename cgid=5 name=Op"},
+		{&ChannelGroupCopyCommand{SourceChannelGroupID: 5, TargetChannelGroupID: 8}, "channelgroupcopy scgid=5 tcgid=8 name= type=0"},
+		{&ChannelGroupClientListCommand{ChannelId: 3, ChannelGroupID: 5}, "channelgroupclientlist cid=3 cgid=5"},
+		{&SetClientChannelGroupCommand{ChannelGroupID: 5, ChannelId: 3, ClientDBId: 12}, "setclientchannelgroup cgid=5 cid=3 cldbid=12"},
+	}
+	for _, c := range cases {
+		str, err := MarshalCommand(c.cmd)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if str != c.expected {
+			t.Fatalf("expected %q, got: %q", c.expected, str)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package serverquery
 
 import (
 	"bytes"
+	"context"
 )
 
 // Command is an instance of a serverquery command.
@@ -12,6 +13,18 @@ type Command interface {
 	GetResponseType() interface{}
 }
 
+// CommandExecutor executes commands and waits for the result.
+type CommandExecutor interface {
+	// ExecuteCommand executes a command, returning an instance of the response type.
+	ExecuteCommand(ctx context.Context, command Command) (interface{}, error)
+}
+
+// Commands provides the typed command helpers on top of a CommandExecutor.
+// It is embedded in ServerQueryAPI and WebQueryClient so the helpers work with either transport.
+type Commands struct {
+	CommandExecutor
+}
+
 // MarshalCommand marshals a command to a string.
 func MarshalCommand(cmd Command, args ...interface{}) (string, error) {
 	var result bytes.Buffer
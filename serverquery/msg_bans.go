@@ -0,0 +1,229 @@
+package serverquery
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BanEntry is an entry in the ban list.
+type BanEntry struct {
+	// Id is the ID of the ban.
+	Id int `serverquery:"banid"`
+	// IP is the banned IP address regex, if any.
+	IP string `serverquery:"ip"`
+	// Name is the banned nickname regex, if any.
+	Name string `serverquery:"name"`
+	// UID is the banned client unique id, if any.
+	UID string `serverquery:"uid"`
+	// MyTSID is the banned myTeamSpeak id, if any.
+	MyTSID string `serverquery:"mytsid"`
+	// LastNickname is the last nickname of the banned client.
+	LastNickname string `serverquery:"lastnickname"`
+	// Created is the unix timestamp of the ban.
+	Created int `serverquery:"created"`
+	// Duration is the length of the ban in seconds, or 0 if permanent.
+	Duration int `serverquery:"duration"`
+	// InvokerName is the nickname of the client that created the ban.
+	InvokerName string `serverquery:"invokername"`
+	// InvokerDBId is the database ID of the client that created the ban.
+	InvokerDBId int `serverquery:"invokercldbid"`
+	// InvokerUID is the unique id of the client that created the ban.
+	InvokerUID string `serverquery:"invokeruid"`
+	// Reason is the reason of the ban.
+	Reason string `serverquery:"reason"`
+	// Enforcements is the number of times the ban was enforced.
+	Enforcements int `serverquery:"enforcements"`
+}
+
+// CreatedAt returns the time the ban was created.
+func (b *BanEntry) CreatedAt() time.Time {
+	return time.Unix(int64(b.Created), 0)
+}
+
+// Length returns the length of the ban, or 0 if permanent.
+func (b *BanEntry) Length() time.Duration {
+	return time.Duration(b.Duration) * time.Second
+}
+
+// IsPermanent checks if the ban does not expire.
+func (b *BanEntry) IsPermanent() bool {
+	return b.Duration == 0
+}
+
+// banSeconds converts a ban length to seconds, rounding up so short bans do not become permanent.
+func banSeconds(length time.Duration) int {
+	if length <= 0 {
+		return 0
+	}
+	return int((length + time.Second - 1) / time.Second)
+}
+
+// BanListCommand lists the bans.
+type BanListCommand struct {
+	// Start is the offset of the first entry.
+	Start int `serverquery:"start,omitempty"`
+	// Duration is the number of entries to list.
+	Duration int `serverquery:"duration,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *BanListCommand) GetResponseType() interface{} {
+	return make([]*BanEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *BanListCommand) GetCommandName() string {
+	return "banlist"
+}
+
+// GetBanList returns the list of bans.
+// Returns an empty list if there are no bans.
+func (c *Commands) GetBanList(ctx context.Context) ([]*BanEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &BanListCommand{})
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]*BanEntry, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*BanEntry), nil
+}
+
+// BanRule selects the clients a ban applies to.
+// At least one field must be set.
+type BanRule struct {
+	// IP is the IP address regex to ban.
+	IP string `serverquery:"ip,omitempty"`
+	// Name is the nickname regex to ban.
+	Name string `serverquery:"name,omitempty"`
+	// UID is the client unique id to ban.
+	UID string `serverquery:"uid,omitempty"`
+	// MyTSID is the myTeamSpeak id to ban.
+	MyTSID string `serverquery:"mytsid,omitempty"`
+}
+
+// BanResponse is a ban id returned by banadd and banclient.
+type BanResponse struct {
+	// Id is the ID of the ban.
+	Id int `serverquery:"banid"`
+}
+
+// BanAddCommand adds a ban rule.
+type BanAddCommand struct {
+	BanRule
+
+	// Time is the length of the ban in seconds, or 0 for permanent.
+	Time int `serverquery:"time,omitempty"`
+	// Reason is the reason of the ban.
+	Reason string `serverquery:"banreason,omitempty"`
+	// LastNickname is the nickname shown in the ban list.
+	LastNickname string `serverquery:"lastnickname,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *BanAddCommand) GetResponseType() interface{} {
+	return &BanResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *BanAddCommand) GetCommandName() string {
+	return "banadd"
+}
+
+// AddBan adds a ban rule, returning the ban id.
+// A length of 0 bans permanently.
+func (c *Commands) AddBan(ctx context.Context, rule BanRule, length time.Duration, reason string) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &BanAddCommand{
+		BanRule: rule,
+		Time:    banSeconds(length),
+		Reason:  reason,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return i.(*BanResponse).Id, nil
+}
+
+// BanClientCommand bans a connected client.
+type BanClientCommand struct {
+	// ClientId is the ID of the client.
+	ClientId int `serverquery:"clid"`
+	// Time is the length of the ban in seconds, or 0 for permanent.
+	Time int `serverquery:"time,omitempty"`
+	// Reason is the reason of the ban.
+	Reason string `serverquery:"banreason,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *BanClientCommand) GetResponseType() interface{} {
+	return make([]*BanResponse, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *BanClientCommand) GetCommandName() string {
+	return "banclient"
+}
+
+// BanClient bans a connected client and kicks it from the server.
+// Returns the ids of the ban rules created, usually one each for the ip and unique id.
+// A length of 0 bans permanently.
+func (c *Commands) BanClient(ctx context.Context, clid int, length time.Duration, reason string) ([]int, error) {
+	i, err := c.ExecuteCommand(ctx, &BanClientCommand{
+		ClientId: clid,
+		Time:     banSeconds(length),
+		Reason:   reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bans := i.([]*BanResponse)
+	res := make([]int, len(bans))
+	for idx, ban := range bans {
+		res[idx] = ban.Id
+	}
+	return res, nil
+}
+
+// BanDelCommand deletes a ban.
+type BanDelCommand struct {
+	// Id is the ID of the ban.
+	Id int `serverquery:"banid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *BanDelCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *BanDelCommand) GetCommandName() string {
+	return "bandel"
+}
+
+// DeleteBan deletes a ban.
+func (c *Commands) DeleteBan(ctx context.Context, banID int) error {
+	_, err := c.ExecuteCommand(ctx, &BanDelCommand{Id: banID})
+	return err
+}
+
+// BanDelAllCommand deletes all bans.
+type BanDelAllCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *BanDelAllCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *BanDelAllCommand) GetCommandName() string {
+	return "bandelall"
+}
+
+// DeleteAllBans deletes all bans.
+func (c *Commands) DeleteAllBans(ctx context.Context) error {
+	_, err := c.ExecuteCommand(ctx, &BanDelAllCommand{})
+	return err
+}
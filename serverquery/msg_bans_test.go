@@ -0,0 +1,63 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestBans tests the ban commands.
+func TestBans(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"banlist": {
+			"banid=5 ip name uid=alice= mytsid lastnickname=alice created=1600000000 duration=3600 invokername=admin invokercldbid=1 invokeruid=admin= reason=spam enforcements=2|" +
+				"banid=6 ip=10\\.0\\.0\\.1 name uid mytsid lastnickname created=1600000100 duration=0 invokername=admin invokercldbid=1 invokeruid=admin= reason enforcements=0",
+			"error id=0 msg=ok",
+		},
+		"banclient clid=7 time=2 banreason=flooding": {
+			"banid=8",
+			"banid=9",
+			"error id=0 msg=ok",
+		},
+		"banadd uid=bob= banreason=cheating": {
+			"banid=10",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	bans, err := api.GetBanList(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(bans) != 2 {
+		t.Fatalf("expected 2 bans, got %d", len(bans))
+	}
+	if bans[0].UID != "alice=" || bans[0].Length() != time.Hour || bans[0].Enforcements != 2 || bans[0].InvokerDBId != 1 {
+		t.Fatalf("ban parsed incorrectly: %#v", bans[0])
+	}
+	if bans[1].IP != "10\\.0\\.0\\.1" || !bans[1].IsPermanent() {
+		t.Fatalf("ban parsed incorrectly: %#v", bans[1])
+	}
+
+	// sub-second lengths round up instead of becoming permanent
+	ids, err := api.BanClient(ctx, 7, 1500*time.Millisecond, "flooding")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(ids) != 2 || ids[0] != 8 || ids[1] != 9 {
+		t.Fatalf("expected ban ids [8 9], got %v", ids)
+	}
+
+	id, err := api.AddBan(ctx, BanRule{UID: "bob="}, 0, "cheating")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if id != 10 {
+		t.Fatalf("expected ban id 10, got %d", id)
+	}
+	s.expectReceived(t, "banlist", "banclient clid=7 time=2 banreason=flooding", "banadd uid=bob= banreason=cheating")
+}
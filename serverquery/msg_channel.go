@@ -2,6 +2,9 @@ package serverquery
 
 import (
 	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
 )
 
 // ChannelState contains transient information about a channel.
@@ -99,7 +102,7 @@ func (c *GetChannelListCommand) GetCommandName() string {
 }
 
 // GetChannelList returns the list of channels.
-func (c *ServerQueryAPI) GetChannelList(ctx context.Context) ([]*ChannelListEntry, error) {
+func (c *Commands) GetChannelList(ctx context.Context) ([]*ChannelListEntry, error) {
 	i, err := c.ExecuteCommand(ctx, &GetChannelListCommand{})
 	if err != nil {
 		return nil, err
@@ -130,7 +133,7 @@ func (c *GetChannelInfoCommand) GetCommandName() string {
 }
 
 // GetChannelInfo returns information about a channel.
-func (c *ServerQueryAPI) GetChannelInfo(ctx context.Context, channelID int) (*GetChannelInfoResponse, error) {
+func (c *Commands) GetChannelInfo(ctx context.Context, channelID int) (*GetChannelInfoResponse, error) {
 	i, err := c.ExecuteCommand(ctx, &GetChannelInfoCommand{Id: channelID})
 	if err != nil {
 		return nil, err
@@ -139,3 +142,250 @@ func (c *ServerQueryAPI) GetChannelInfo(ctx context.Context, channelID int) (*Ge
 	r.Id = channelID
 	return r, nil
 }
+
+// MoveChannelCommand moves a channel to a new parent and position.
+type MoveChannelCommand struct {
+	// Id is the id of the channel to move.
+	Id int `serverquery:"cid"`
+	// ParentId is the id of the new parent, or 0 for the root.
+	ParentId int `serverquery:"cpid"`
+	// Order is the id of the channel to sort after, or 0 to sort first.
+	Order int `serverquery:"order"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *MoveChannelCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *MoveChannelCommand) GetCommandName() string {
+	return "channelmove"
+}
+
+// MoveChannel moves a channel below parentID, sorted after the channel order.
+func (c *Commands) MoveChannel(ctx context.Context, channelID, parentID, order int) error {
+	_, err := c.ExecuteCommand(ctx, &MoveChannelCommand{
+		Id:       channelID,
+		ParentId: parentID,
+		Order:    order,
+	})
+	return err
+}
+
+// ChannelProperties are the properties that can be set with channelcreate and channeledit.
+// Nil fields are not sent. The tags match ChannelFullInfo.
+type ChannelProperties struct {
+	// Name is the name of the channel.
+	Name *string `serverquery:"channel_name,omitempty"`
+	// Topic is the topic of the channel.
+	Topic *string `serverquery:"channel_topic,omitempty"`
+	// Description is the description of the channel.
+	Description *string `serverquery:"channel_description,omitempty"`
+	// Password is the channel password.
+	Password *string `serverquery:"channel_password,omitempty"`
+	// ParentId is the identifier of the parent, only used by channelcreate.
+	ParentId *int `serverquery:"cpid,omitempty"`
+	// Order is the id of the channel to sort after.
+	Order *int `serverquery:"channel_order,omitempty"`
+	// IsDefault is set if the channel is the default channel.
+	IsDefault *bool `serverquery:"channel_flag_default,omitempty"`
+	// IsPermanent is set if the channel is permanent.
+	IsPermanent *bool `serverquery:"channel_flag_permanent,omitempty"`
+	// IsSemiPermanent is set if the channel is semi-permanent.
+	IsSemiPermanent *bool `serverquery:"channel_flag_semi_permanent,omitempty"`
+	// MaxClients sets the maximum client count.
+	MaxClients *int `serverquery:"channel_maxclients,omitempty"`
+	// MaxFamilyClients sets the maximum family client count.
+	MaxFamilyClients *int `serverquery:"channel_maxfamilyclients,omitempty"`
+	// MaxClientsUnlimited is set when the channel has unlimited client count.
+	MaxClientsUnlimited *bool `serverquery:"channel_flag_maxclients_unlimited,omitempty"`
+	// MaxFamilyClientsUnlimited is set when the channel has unlimited family client count.
+	MaxFamilyClientsUnlimited *bool `serverquery:"channel_flag_maxfamilyclients_unlimited,omitempty"`
+	// MaxFamilyClientsInherited is set when the channel inherits its parent max family clients count.
+	MaxFamilyClientsInherited *bool `serverquery:"channel_flag_maxfamilyclients_inherited,omitempty"`
+	// Codec is the ID of the codec.
+	Codec *int `serverquery:"channel_codec,omitempty"`
+	// CodecQuality is the quality between 1-10 of the codec.
+	CodecQuality *int `serverquery:"channel_codec_quality,omitempty"`
+	// CodecLatencyFactor is the latency factor of the codec.
+	CodecLatencyFactor *int `serverquery:"channel_codec_latency_factor,omitempty"`
+	// CodecIsUnencrypted is set if the codec is not encrypted.
+	CodecIsUnencrypted *bool `serverquery:"channel_codec_is_unencrypted,omitempty"`
+	// DeleteDelay is the delay deleting the channel.
+	DeleteDelay *int `serverquery:"channel_delete_delay,omitempty"`
+	// NeededTalkPower is the needed channel talk power.
+	NeededTalkPower *int `serverquery:"channel_needed_talk_power,omitempty"`
+	// PhoneticName is the phonetic name of the channel.
+	PhoneticName *string `serverquery:"channel_name_phonetic,omitempty"`
+	// IconId is the id of the icon for the channel.
+	IconId *int `serverquery:"channel_icon_id,omitempty"`
+}
+
+// ChannelPropertiesDiff returns the properties that differ between two channel infos.
+// Only fields of updated that differ from current are set, so the result
+// can be passed to EditChannel.
+func ChannelPropertiesDiff(current, updated *ChannelFullInfo) *ChannelProperties {
+	props := &ChannelProperties{}
+	diffProperties(reflect.ValueOf(current).Elem(), reflect.ValueOf(updated).Elem(), reflect.ValueOf(props).Elem())
+	return props
+}
+
+// diffProperties sets the pointer fields of props whose tags match differing fields.
+func diffProperties(current, updated, props reflect.Value) {
+	propFields := make(map[string]reflect.Value)
+	for i := 0; i < props.NumField(); i++ {
+		if sqtag, ok := props.Type().Field(i).Tag.Lookup("serverquery"); ok {
+			sqtag, _ = parseTag(sqtag)
+			propFields[sqtag] = props.Field(i)
+		}
+	}
+
+	var walk func(current, updated reflect.Value)
+	walk = func(current, updated reflect.Value) {
+		for i := 0; i < current.NumField(); i++ {
+			fieldInfo := current.Type().Field(i)
+			if fieldInfo.Anonymous && fieldInfo.Type.Kind() == reflect.Struct {
+				walk(current.Field(i), updated.Field(i))
+				continue
+			}
+			sqtag, ok := fieldInfo.Tag.Lookup("serverquery")
+			if !ok {
+				continue
+			}
+			sqtag, _ = parseTag(sqtag)
+			propField, ok := propFields[sqtag]
+			if !ok || reflect.DeepEqual(current.Field(i).Interface(), updated.Field(i).Interface()) {
+				continue
+			}
+			val := reflect.New(propField.Type().Elem())
+			val.Elem().Set(updated.Field(i))
+			propField.Set(val)
+		}
+	}
+	walk(current, updated)
+}
+
+// ChannelCreateResponse is the response to channelcreate.
+type ChannelCreateResponse struct {
+	// Id is the identifier of the new channel.
+	Id int `serverquery:"cid"`
+}
+
+// ChannelCreateCommand creates a channel.
+type ChannelCreateCommand struct {
+	ChannelProperties
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelCreateCommand) GetResponseType() interface{} {
+	return &ChannelCreateResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelCreateCommand) GetCommandName() string {
+	return "channelcreate"
+}
+
+// CreateChannel creates a channel, returning the new channel id.
+// The name overrides props.Name.
+func (c *Commands) CreateChannel(ctx context.Context, name string, props ChannelProperties) (int, error) {
+	props.Name = &name
+	i, err := c.ExecuteCommand(ctx, &ChannelCreateCommand{ChannelProperties: props})
+	if err != nil {
+		return 0, err
+	}
+	return i.(*ChannelCreateResponse).Id, nil
+}
+
+// ChannelEditCommand changes the properties of a channel.
+type ChannelEditCommand struct {
+	// Id is the id of the channel.
+	Id int `serverquery:"cid"`
+
+	ChannelProperties
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelEditCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelEditCommand) GetCommandName() string {
+	return "channeledit"
+}
+
+// EditChannel changes the properties of a channel.
+func (c *Commands) EditChannel(ctx context.Context, channelID int, props ChannelProperties) error {
+	_, err := c.ExecuteCommand(ctx, &ChannelEditCommand{
+		Id:                channelID,
+		ChannelProperties: props,
+	})
+	return err
+}
+
+// ChannelDeleteCommand deletes a channel.
+type ChannelDeleteCommand struct {
+	// Id is the id of the channel.
+	Id int `serverquery:"cid"`
+	// Force deletes the channel even if clients are in it.
+	Force bool `serverquery:"force"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelDeleteCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelDeleteCommand) GetCommandName() string {
+	return "channeldelete"
+}
+
+// DeleteChannel deletes a channel and its subchannels.
+// If force is set, clients in the channels are moved to the default channel.
+func (c *Commands) DeleteChannel(ctx context.Context, channelID int, force bool) error {
+	_, err := c.ExecuteCommand(ctx, &ChannelDeleteCommand{
+		Id:    channelID,
+		Force: force,
+	})
+	return err
+}
+
+// ChannelFindEntry is a channel matched by channelfind.
+type ChannelFindEntry struct {
+	// Id is the identifier of the channel.
+	Id int `serverquery:"cid"`
+	// Name is the name of the channel.
+	Name string `serverquery:"channel_name"`
+}
+
+// ChannelFindCommand searches for channels by name.
+type ChannelFindCommand struct {
+	// Pattern is the channel name pattern to search for.
+	Pattern string `serverquery:"pattern"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelFindCommand) GetResponseType() interface{} {
+	return make([]*ChannelFindEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelFindCommand) GetCommandName() string {
+	return "channelfind"
+}
+
+// FindChannels returns the channels with a name matching the pattern.
+// Returns an empty list if no channels match.
+func (c *Commands) FindChannels(ctx context.Context, pattern string) ([]*ChannelFindEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &ChannelFindCommand{Pattern: pattern})
+	if err != nil {
+		if errors.Is(err, ErrInvalidChannelID) || errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]*ChannelFindEntry, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*ChannelFindEntry), nil
+}
@@ -0,0 +1,63 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+)
+
+// TestChannelPropertiesDiff tests converting a channel info diff to a channeledit call.
+func TestChannelPropertiesDiff(t *testing.T) {
+	current := &ChannelFullInfo{ChannelBasicInfo: ChannelBasicInfo{
+		Id:         5,
+		Name:       "Lobby",
+		Topic:      "Welcome",
+		MaxClients: 10,
+		IsDefault:  true,
+	}}
+	updated := *current
+	updated.Name = "Main Lobby"
+	updated.MaxClients = 0
+	updated.NeededTalkPower = 20
+
+	props := ChannelPropertiesDiff(current, &updated)
+	str, err := MarshalCommand(&ChannelEditCommand{Id: 5, ChannelProperties: *props})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := "channeledit cid=5 channel_name=Main\\sLobby channel_maxclients=0 channel_needed_talk_power=20"
+	if str != expected {
+		t.Fatalf("expected %s, got: %s", expected, str)
+	}
+}
+
+// TestChannelCreateDelete tests creating and deleting a channel.
+func TestChannelCreateDelete(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"channelcreate channel_name=Games cpid=1 channel_flag_permanent=1": {
+			"cid=12",
+			"error id=0 msg=ok",
+		},
+		"channeldelete cid=12 force=1": {"error id=0 msg=ok"},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	parentID, permanent := 1, true
+	cid, err := api.CreateChannel(ctx, "Games", ChannelProperties{
+		ParentId:    &parentID,
+		IsPermanent: &permanent,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if cid != 12 {
+		t.Fatalf("expected channel id 12, got %d", cid)
+	}
+
+	if err := api.DeleteChannel(ctx, cid, true); err != nil {
+		t.Fatal(err.Error())
+	}
+	s.expectReceived(t, "channelcreate channel_name=Games cpid=1 channel_flag_permanent=1", "channeldelete cid=12 force=1")
+}
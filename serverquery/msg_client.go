@@ -2,12 +2,16 @@ package serverquery
 
 import (
 	"context"
+
+	"github.com/pkg/errors"
 )
 
 // ClientBasicInfo is the basic information given out in the client list.
 type ClientBasicInfo struct {
 	// Id is the ID of the client.
 	Id int `serverquery:"clid"`
+	// ChannelId is the ID of the channel the client is in.
+	ChannelId int `serverquery:"cid"`
 	// DatabaseId is the ID of the client in the database.
 	DatabaseId int `serverquery:"client_database_id"`
 	// Nickname is the nickname of the client.
@@ -32,7 +36,7 @@ func (c *GetClientListCommand) GetCommandName() string {
 }
 
 // GetClientList returns the list of the clients.
-func (c *ServerQueryAPI) GetClientList(ctx context.Context) ([]*ClientBasicInfo, error) {
+func (c *Commands) GetClientList(ctx context.Context) ([]*ClientBasicInfo, error) {
 	i, err := c.ExecuteCommand(ctx, &GetClientListCommand{})
 	if err != nil {
 		return nil, err
@@ -40,9 +44,101 @@ func (c *ServerQueryAPI) GetClientList(ctx context.Context) ([]*ClientBasicInfo,
 	return i.([]*ClientBasicInfo), nil
 }
 
+// ClientListEntry is an entry in the client list with optional details.
+// The detail fields are only set if requested in ClientListDetailsCommand.
+type ClientListEntry struct {
+	ClientBasicInfo
+
+	// Version is the client version (-info).
+	Version string `serverquery:"client_version"`
+	// Platform is the client platform (-info).
+	Platform string `serverquery:"client_platform"`
+	// IdleTime is the number of milliseconds the client has been idle (-times).
+	IdleTime int `serverquery:"client_idle_time"`
+	// Created is the unix timestamp of the first connection of the client (-times).
+	Created int `serverquery:"client_created"`
+	// LastConnected is the unix timestamp of the last connection of the client (-times).
+	LastConnected int `serverquery:"client_lastconnected"`
+	// Talking indicates if the client is talking (-voice).
+	Talking bool `serverquery:"client_flag_talking"`
+	// InputMuted indicates if the client mic is muted (-voice).
+	InputMuted bool `serverquery:"client_input_muted"`
+	// OutputMuted indicates if the client speakers are muted (-voice).
+	OutputMuted bool `serverquery:"client_output_muted"`
+	// HasInputHardware indicates if the client has a mic (-voice).
+	HasInputHardware bool `serverquery:"client_input_hardware"`
+	// HasOutputHardware indicates if the client has speakers (-voice).
+	HasOutputHardware bool `serverquery:"client_output_hardware"`
+	// TalkPower is the talk power of the client (-voice).
+	TalkPower int `serverquery:"client_talk_power"`
+	// IsTalker indicates if the client is a talker (-voice).
+	IsTalker bool `serverquery:"client_is_talker"`
+	// IsPrioritySpeaker indicates if the client is a priority speaker (-voice).
+	IsPrioritySpeaker bool `serverquery:"client_is_priority_speaker"`
+	// IsRecording indicates if the client is recording (-voice).
+	IsRecording bool `serverquery:"client_is_recording"`
+	// IsChannelCommander indicates if the client is a channel commander (-voice).
+	IsChannelCommander bool `serverquery:"client_is_channel_commander"`
+	// Country is the country code of the client (-country).
+	Country string `serverquery:"client_country"`
+	// IP is the ip address of the client (-ip).
+	IP string `serverquery:"connection_client_ip"`
+}
+
+// ClientListDetailsCommand requests the client list with additional details.
+type ClientListDetailsCommand struct {
+	// Info requests the client version and platform.
+	Info bool
+	// Times requests the idle, created and last connected times.
+	Times bool
+	// Voice requests the talking, muted and talk power state.
+	Voice bool
+	// Country requests the client country.
+	Country bool
+	// IP requests the client ip address.
+	IP bool
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientListDetailsCommand) GetResponseType() interface{} {
+	return make([]*ClientListEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientListDetailsCommand) GetCommandName() string {
+	name := "clientlist -uid"
+	if c.Info {
+		name += " -info"
+	}
+	if c.Times {
+		name += " -times"
+	}
+	if c.Voice {
+		name += " -voice"
+	}
+	if c.Country {
+		name += " -country"
+	}
+	if c.IP {
+		name += " -ip"
+	}
+	return name
+}
+
+// GetClientListDetails returns the list of the clients with the requested details.
+func (c *Commands) GetClientListDetails(ctx context.Context, cmd *ClientListDetailsCommand) ([]*ClientListEntry, error) {
+	i, err := c.ExecuteCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*ClientListEntry), nil
+}
+
 // ClientInfo contains client information.
 type ClientInfo struct {
 	ClientBasicInfo
+	// ClientConnectionInfo contains the connection statistics of the client.
+	ClientConnectionInfo
 
 	// ClientIdleTime is the time the client has been idle.
 	ClientIdleTime int `serverquery:"client_idle_time"`
@@ -121,7 +217,7 @@ func (c *GetClientInfoCommand) GetCommandName() string {
 }
 
 // GetClientInfo returns the info of a client.
-func (c *ServerQueryAPI) GetClientInfo(ctx context.Context, clid int) (*ClientInfo, error) {
+func (c *Commands) GetClientInfo(ctx context.Context, clid int) (*ClientInfo, error) {
 	i, err := c.ExecuteCommand(ctx, &GetClientInfoCommand{ClientId: clid})
 	if err != nil {
 		return nil, err
@@ -153,7 +249,7 @@ func (c *SendTextMessageCommand) GetCommandName() string {
 
 // SendTextMessage sends a text message to a target.
 // targetType: client=1, channel=2, server=3
-func (c *ServerQueryAPI) SendTextMessage(
+func (c *Commands) SendTextMessage(
 	ctx context.Context,
 	targetType int,
 	targetId int,
@@ -166,3 +262,365 @@ func (c *ServerQueryAPI) SendTextMessage(
 	})
 	return err
 }
+
+// ClientUpdateCommand changes the properties of the query client.
+type ClientUpdateCommand struct {
+	// Nickname is the nickname of the query client.
+	Nickname string `serverquery:"client_nickname"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientUpdateCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientUpdateCommand) GetCommandName() string {
+	return "clientupdate"
+}
+
+// SetNickname changes the nickname of the query client.
+func (c *ServerQueryAPI) SetNickname(ctx context.Context, nickname string) error {
+	if _, err := c.ExecuteCommand(ctx, &ClientUpdateCommand{Nickname: nickname}); err != nil {
+		return err
+	}
+	c.session.setNickname(nickname)
+	return nil
+}
+
+// Kick reason ids for clientkick.
+const (
+	// KickReasonChannel kicks the client from its channel to the default channel.
+	KickReasonChannel = 4
+	// KickReasonServer kicks the client from the server.
+	KickReasonServer = 5
+)
+
+// ClientKickCommand kicks one or more clients.
+type ClientKickCommand struct {
+	// ClientIds are the IDs of the clients to kick.
+	ClientIds []int `serverquery:"clid,pipe"`
+	// ReasonId is KickReasonChannel or KickReasonServer.
+	ReasonId int `serverquery:"reasonid"`
+	// ReasonMessage is the message shown to the clients, max 40 characters.
+	ReasonMessage string `serverquery:"reasonmsg,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientKickCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientKickCommand) GetCommandName() string {
+	return "clientkick"
+}
+
+// KickClients kicks clients from their channel or the server.
+// reasonID: KickReasonChannel or KickReasonServer
+func (c *Commands) KickClients(
+	ctx context.Context,
+	reasonID int,
+	reasonMessage string,
+	clientIDs ...int,
+) error {
+	_, err := c.ExecuteCommand(ctx, &ClientKickCommand{
+		ClientIds:     clientIDs,
+		ReasonId:      reasonID,
+		ReasonMessage: reasonMessage,
+	})
+	return err
+}
+
+// ClientMoveCommand moves one or more clients to a channel.
+type ClientMoveCommand struct {
+	// ClientIds are the IDs of the clients to move.
+	ClientIds []int `serverquery:"clid,pipe"`
+	// ChannelId is the ID of the target channel.
+	ChannelId int `serverquery:"cid"`
+	// ChannelPassword is the password of the target channel, if any.
+	ChannelPassword string `serverquery:"cpw,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientMoveCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientMoveCommand) GetCommandName() string {
+	return "clientmove"
+}
+
+// MoveClients moves clients to a channel.
+// channelPassword can be empty if the channel has no password.
+func (c *Commands) MoveClients(
+	ctx context.Context,
+	channelID int,
+	channelPassword string,
+	clientIDs ...int,
+) error {
+	_, err := c.ExecuteCommand(ctx, &ClientMoveCommand{
+		ClientIds:       clientIDs,
+		ChannelId:       channelID,
+		ChannelPassword: channelPassword,
+	})
+	return err
+}
+
+// ClientPokeCommand pokes a client with a popup message.
+type ClientPokeCommand struct {
+	// ClientId is the ID of the client.
+	ClientId int `serverquery:"clid"`
+	// Message is the message to show.
+	Message string `serverquery:"msg"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientPokeCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientPokeCommand) GetCommandName() string {
+	return "clientpoke"
+}
+
+// PokeClient pokes a client with a popup message.
+func (c *Commands) PokeClient(ctx context.Context, clid int, message string) error {
+	_, err := c.ExecuteCommand(ctx, &ClientPokeCommand{
+		ClientId: clid,
+		Message:  message,
+	})
+	return err
+}
+
+// ClientEditProperties are the properties that can be changed with clientedit.
+// Nil fields are left unchanged.
+type ClientEditProperties struct {
+	// Description is the description of the client.
+	Description *string `serverquery:"client_description,omitempty"`
+	// IsTalker indicates if the client is granted talk power.
+	IsTalker *bool `serverquery:"client_is_talker,omitempty"`
+	// IconId is the icon ID of the client.
+	IconId *int `serverquery:"client_icon_id,omitempty"`
+}
+
+// ClientEditCommand changes the properties of a client.
+type ClientEditCommand struct {
+	ClientEditProperties
+
+	// ClientId is the ID of the client.
+	ClientId int `serverquery:"clid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientEditCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientEditCommand) GetCommandName() string {
+	return "clientedit"
+}
+
+// EditClient changes the properties of a client.
+func (c *Commands) EditClient(ctx context.Context, clid int, props ClientEditProperties) error {
+	_, err := c.ExecuteCommand(ctx, &ClientEditCommand{
+		ClientEditProperties: props,
+		ClientId:             clid,
+	})
+	return err
+}
+
+// ClientFindEntry is a client matched by clientfind.
+type ClientFindEntry struct {
+	// Id is the ID of the client.
+	Id int `serverquery:"clid"`
+	// Nickname is the nickname of the client.
+	Nickname string `serverquery:"client_nickname"`
+}
+
+// ClientFindCommand searches for clients by nickname.
+type ClientFindCommand struct {
+	// Pattern is the nickname pattern to search for.
+	Pattern string `serverquery:"pattern"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientFindCommand) GetResponseType() interface{} {
+	return make([]*ClientFindEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientFindCommand) GetCommandName() string {
+	return "clientfind"
+}
+
+// FindClients returns the online clients with a nickname matching the pattern.
+// Returns an empty list if no clients match.
+func (c *Commands) FindClients(ctx context.Context, pattern string) ([]*ClientFindEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientFindCommand{Pattern: pattern})
+	if err != nil {
+		if errors.Is(err, ErrInvalidClientID) {
+			return make([]*ClientFindEntry, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*ClientFindEntry), nil
+}
+
+// ClientIdsEntry is an online connection of a client unique id.
+type ClientIdsEntry struct {
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"cluid"`
+	// ClientId is the ID of the connected client.
+	ClientId int `serverquery:"clid"`
+	// Nickname is the nickname of the connected client.
+	Nickname string `serverquery:"name"`
+}
+
+// ClientGetIdsCommand lists the connected clients with a unique id.
+type ClientGetIdsCommand struct {
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"cluid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientGetIdsCommand) GetResponseType() interface{} {
+	return make([]*ClientIdsEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientGetIdsCommand) GetCommandName() string {
+	return "clientgetids"
+}
+
+// GetClientIds returns the connected clients with a unique id.
+func (c *Commands) GetClientIds(ctx context.Context, uid string) ([]*ClientIdsEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientGetIdsCommand{UniqueIdentifier: uid})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*ClientIdsEntry), nil
+}
+
+// ClientNameInfo contains the database id and last nickname of a unique id.
+type ClientNameInfo struct {
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"cluid"`
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+	// Nickname is the last nickname of the client.
+	Nickname string `serverquery:"name"`
+}
+
+// ClientGetDBIdFromUIDCommand looks up the database id of a unique id.
+type ClientGetDBIdFromUIDCommand struct {
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"cluid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientGetDBIdFromUIDCommand) GetResponseType() interface{} {
+	return &ClientNameInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientGetDBIdFromUIDCommand) GetCommandName() string {
+	return "clientgetdbidfromuid"
+}
+
+// GetClientDBIdFromUID returns the database id of a unique id.
+func (c *Commands) GetClientDBIdFromUID(ctx context.Context, uid string) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientGetDBIdFromUIDCommand{UniqueIdentifier: uid})
+	if err != nil {
+		return 0, err
+	}
+	return i.(*ClientNameInfo).DatabaseId, nil
+}
+
+// ClientGetNameFromUIDCommand looks up the database id and nickname of a unique id.
+type ClientGetNameFromUIDCommand struct {
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"cluid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientGetNameFromUIDCommand) GetResponseType() interface{} {
+	return &ClientNameInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientGetNameFromUIDCommand) GetCommandName() string {
+	return "clientgetnamefromuid"
+}
+
+// GetClientNameFromUID returns the database id and last nickname of a unique id.
+func (c *Commands) GetClientNameFromUID(ctx context.Context, uid string) (*ClientNameInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientGetNameFromUIDCommand{UniqueIdentifier: uid})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*ClientNameInfo), nil
+}
+
+// ClientGetNameFromDBIdCommand looks up the unique id and nickname of a database id.
+type ClientGetNameFromDBIdCommand struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientGetNameFromDBIdCommand) GetResponseType() interface{} {
+	return &ClientNameInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientGetNameFromDBIdCommand) GetCommandName() string {
+	return "clientgetnamefromdbid"
+}
+
+// GetClientNameFromDBId returns the unique id and last nickname of a database id.
+func (c *Commands) GetClientNameFromDBId(ctx context.Context, cldbid int) (*ClientNameInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientGetNameFromDBIdCommand{DatabaseId: cldbid})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*ClientNameInfo), nil
+}
+
+// ClientUIDInfo contains the unique id of a connected client.
+type ClientUIDInfo struct {
+	// ClientId is the ID of the connected client.
+	ClientId int `serverquery:"clid"`
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"cluid"`
+	// Nickname is the nickname of the connected client.
+	Nickname string `serverquery:"nickname"`
+}
+
+// ClientGetUIDFromClidCommand looks up the unique id of a connected client.
+type ClientGetUIDFromClidCommand struct {
+	// ClientId is the ID of the connected client.
+	ClientId int `serverquery:"clid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientGetUIDFromClidCommand) GetResponseType() interface{} {
+	return &ClientUIDInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientGetUIDFromClidCommand) GetCommandName() string {
+	return "clientgetuidfromclid"
+}
+
+// GetClientUIDFromClid returns the unique id of a connected client.
+func (c *Commands) GetClientUIDFromClid(ctx context.Context, clid int) (*ClientUIDInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientGetUIDFromClidCommand{ClientId: clid})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*ClientUIDInfo), nil
+}
@@ -0,0 +1,318 @@
+package serverquery
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ClientDBEntry is an entry in the client database list.
+type ClientDBEntry struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"client_unique_identifier"`
+	// Nickname is the last nickname of the client.
+	Nickname string `serverquery:"client_nickname"`
+	// Created is the unix timestamp of the first connection.
+	Created int `serverquery:"client_created"`
+	// LastConnected is the unix timestamp of the last connection.
+	LastConnected int `serverquery:"client_lastconnected"`
+	// TotalConnections is the total number of times the client has connected.
+	TotalConnections int `serverquery:"client_totalconnections"`
+	// Description is the description of the client.
+	Description string `serverquery:"client_description"`
+	// LastIP is the last IP address of the client.
+	LastIP string `serverquery:"client_lastip"`
+}
+
+// ClientDBListEntry is an entry in the clientdblist response.
+type ClientDBListEntry struct {
+	ClientDBEntry
+
+	// Count is the total number of database entries.
+	// Only set on the first entry, when requested with Count.
+	Count int `serverquery:"count"`
+}
+
+// ClientDBListCommand lists a page of the client database.
+type ClientDBListCommand struct {
+	// Start is the offset of the first entry.
+	Start int `serverquery:"start"`
+	// Duration is the number of entries to list.
+	Duration int `serverquery:"duration"`
+	// Count requests the total number of entries.
+	Count bool
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientDBListCommand) GetResponseType() interface{} {
+	return make([]*ClientDBListEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientDBListCommand) GetCommandName() string {
+	if c.Count {
+		return "clientdblist -count"
+	}
+	return "clientdblist"
+}
+
+// GetClientDBList returns a page of the client database and the total number of entries.
+// Returns an empty list if start is past the end of the database.
+func (c *Commands) GetClientDBList(ctx context.Context, start, duration int) ([]*ClientDBEntry, int, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientDBListCommand{
+		Start:    start,
+		Duration: duration,
+		Count:    true,
+	})
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]*ClientDBEntry, 0), 0, nil
+		}
+		return nil, 0, err
+	}
+
+	list := i.([]*ClientDBListEntry)
+	res := make([]*ClientDBEntry, len(list))
+	var count int
+	for idx, entry := range list {
+		res[idx] = &entry.ClientDBEntry
+		if idx == 0 {
+			count = entry.Count
+		}
+	}
+	return res, count, nil
+}
+
+// ClientDBIterator pages through the client database.
+type ClientDBIterator struct {
+	// api is the api to query.
+	api *Commands
+	// pageSize is the number of entries fetched per page.
+	pageSize int
+	// start is the offset of the next page.
+	start int
+	// page is the current page.
+	page []*ClientDBEntry
+	// entry is the current entry.
+	entry *ClientDBEntry
+	// done is set when the last page has been fetched.
+	done bool
+	// err is the error that stopped the iterator.
+	err error
+}
+
+// IterateClientDB returns an iterator over the whole client database, for example:
+//
+//	it := api.IterateClientDB(100)
+//	for it.Next(ctx) {
+//		entry := it.Entry()
+//	}
+//	if err := it.Err(); err != nil {
+//
+// If pageSize is 0, 100 entries are fetched per page.
+func (c *Commands) IterateClientDB(pageSize int) *ClientDBIterator {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	return &ClientDBIterator{api: c, pageSize: pageSize}
+}
+
+// Next advances to the next entry, fetching the next page if necessary.
+// Returns false when the database is exhausted or an error occurs.
+func (it *ClientDBIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 && !it.done {
+		page, _, err := it.api.GetClientDBList(ctx, it.start, it.pageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page
+		it.start += len(page)
+		it.done = len(page) < it.pageSize
+	}
+	if len(it.page) == 0 {
+		it.entry = nil
+		return false
+	}
+	it.entry = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Entry returns the current entry.
+func (it *ClientDBIterator) Entry() *ClientDBEntry {
+	return it.entry
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *ClientDBIterator) Err() error {
+	return it.err
+}
+
+// ClientDBInfo contains the database information about a client.
+type ClientDBInfo struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"client_database_id"`
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string `serverquery:"client_unique_identifier"`
+	// Nickname is the last nickname of the client.
+	Nickname string `serverquery:"client_nickname"`
+	// Created is the unix timestamp of the first connection.
+	Created int `serverquery:"client_created"`
+	// LastConnected is the unix timestamp of the last connection.
+	LastConnected int `serverquery:"client_lastconnected"`
+	// TotalConnections is the total number of times the client has connected.
+	TotalConnections int `serverquery:"client_totalconnections"`
+	// AvatarHash is the hash of the avatar of the client, empty if the client has no avatar.
+	AvatarHash string `serverquery:"client_flag_avatar"`
+	// Description is the description of the client.
+	Description string `serverquery:"client_description"`
+	// MonthBytesUploaded is the number of bytes uploaded this month.
+	MonthBytesUploaded int `serverquery:"client_month_bytes_uploaded"`
+	// MonthBytesDownloaded is the number of bytes downloaded this month.
+	MonthBytesDownloaded int `serverquery:"client_month_bytes_downloaded"`
+	// TotalBytesUploaded is the number of bytes uploaded total.
+	TotalBytesUploaded int `serverquery:"client_total_bytes_uploaded"`
+	// TotalBytesDownloaded is the number of bytes downloaded total.
+	TotalBytesDownloaded int `serverquery:"client_total_bytes_downloaded"`
+	// Base64HashClientUID is the base64 hash of the unique id, used for avatars.
+	Base64HashClientUID string `serverquery:"client_base64HashClientUID"`
+	// LastIP is the last IP address of the client.
+	LastIP string `serverquery:"client_lastip"`
+}
+
+// HasAvatar indicates if the client has an avatar.
+func (i *ClientDBInfo) HasAvatar() bool {
+	return i.AvatarHash != ""
+}
+
+// ClientDBInfoCommand requests the database information about a client.
+type ClientDBInfoCommand struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientDBInfoCommand) GetResponseType() interface{} {
+	return &ClientDBInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientDBInfoCommand) GetCommandName() string {
+	return "clientdbinfo"
+}
+
+// GetClientDBInfo returns the database information about a client.
+func (c *Commands) GetClientDBInfo(ctx context.Context, cldbid int) (*ClientDBInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientDBInfoCommand{DatabaseId: cldbid})
+	if err != nil {
+		return nil, err
+	}
+	r := i.(*ClientDBInfo)
+	r.DatabaseId = cldbid
+	return r, nil
+}
+
+// ClientDBFindEntry is a client matched by clientdbfind.
+type ClientDBFindEntry struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+}
+
+// ClientDBFindCommand searches the client database.
+type ClientDBFindCommand struct {
+	// Pattern is the nickname or unique id pattern to search for.
+	// % matches any characters.
+	Pattern string `serverquery:"pattern"`
+	// ByUID searches by unique id instead of nickname.
+	ByUID bool
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientDBFindCommand) GetResponseType() interface{} {
+	return make([]*ClientDBFindEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientDBFindCommand) GetCommandName() string {
+	if c.ByUID {
+		return "clientdbfind -uid"
+	}
+	return "clientdbfind"
+}
+
+// FindClientDB returns the database ids of clients matching the pattern.
+// Returns an empty list if no clients match.
+func (c *Commands) FindClientDB(ctx context.Context, pattern string, byUID bool) ([]int, error) {
+	i, err := c.ExecuteCommand(ctx, &ClientDBFindCommand{
+		Pattern: pattern,
+		ByUID:   byUID,
+	})
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]int, 0), nil
+		}
+		return nil, err
+	}
+
+	entries := i.([]*ClientDBFindEntry)
+	res := make([]int, len(entries))
+	for idx, entry := range entries {
+		res[idx] = entry.DatabaseId
+	}
+	return res, nil
+}
+
+// ClientDBEditCommand changes the database properties of a client.
+type ClientDBEditCommand struct {
+	ClientEditProperties
+
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientDBEditCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientDBEditCommand) GetCommandName() string {
+	return "clientdbedit"
+}
+
+// EditClientDB changes the database properties of a client.
+func (c *Commands) EditClientDB(ctx context.Context, cldbid int, props ClientEditProperties) error {
+	_, err := c.ExecuteCommand(ctx, &ClientDBEditCommand{
+		ClientEditProperties: props,
+		DatabaseId:           cldbid,
+	})
+	return err
+}
+
+// ClientDBDeleteCommand deletes a client from the database.
+type ClientDBDeleteCommand struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int `serverquery:"cldbid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ClientDBDeleteCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ClientDBDeleteCommand) GetCommandName() string {
+	return "clientdbdelete"
+}
+
+// DeleteClientDB deletes a client from the database.
+func (c *Commands) DeleteClientDB(ctx context.Context, cldbid int) error {
+	_, err := c.ExecuteCommand(ctx, &ClientDBDeleteCommand{DatabaseId: cldbid})
+	return err
+}
@@ -0,0 +1,98 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+)
+
+// TestClientDBIterator tests paging through the client database.
+func TestClientDBIterator(t *testing.T) {
+	_, api := newFakeServer(t, map[string][]string{
+		"clientdblist -count start=0 duration=2": {
+			"cldbid=1 client_unique_identifier=serveradmin client_nickname=ServerAdmin client_created=1500000000 client_lastconnected=1500000000 client_totalconnections=0 client_description client_lastip count=3|" +
+				"cldbid=2 client_unique_identifier=alice= client_nickname=alice client_created=1500000100 client_lastconnected=1600000000 client_totalconnections=12 client_description=Loves\\schess client_lastip=10.0.0.2",
+			"error id=0 msg=ok",
+		},
+		"clientdblist -count start=2 duration=2": {
+			"cldbid=5 client_unique_identifier=bob= client_nickname=bob client_created=1500000200 client_lastconnected=1600000100 client_totalconnections=3 client_description client_lastip=10.0.0.3 count=3",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	entries, count, err := api.GetClientDBList(ctx, 0, 2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if count != 3 || len(entries) != 2 || entries[1].Description != "Loves chess" {
+		t.Fatalf("client db list parsed incorrectly: %d %#v", count, entries)
+	}
+
+	var ids []int
+	it := api.IterateClientDB(2)
+	for it.Next(ctx) {
+		ids = append(ids, it.Entry().DatabaseId)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 5 {
+		t.Fatalf("expected database ids [1 2 5], got %v", ids)
+	}
+}
+
+// TestClientDBEmptyResult tests an empty result is not an error.
+func TestClientDBEmptyResult(t *testing.T) {
+	_, api := newFakeServer(t, map[string][]string{
+		"clientdbfind pattern=nobody": {"error id=1281 msg=database\\sempty\\sresult\\sset"},
+		"clientdbfind -uid pattern=alice=": {
+			"cldbid=2",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	ids, err := api.FindClientDB(ctx, "nobody", false)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no results, got %v", ids)
+	}
+
+	ids, err = api.FindClientDB(ctx, "alice=", true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("expected [2], got %v", ids)
+	}
+}
+
+// TestClientDBInfo tests the database information about a client.
+func TestClientDBInfo(t *testing.T) {
+	_, api := newFakeServer(t, map[string][]string{
+		"clientdbinfo cldbid=2": {
+			"client_unique_identifier=alice= client_nickname=alice client_database_id=2 client_created=1500000100 client_lastconnected=1600000000 client_totalconnections=12 client_flag_avatar=1f8e7e9bbf2bd2a7b3c0c2ba09a2e8a1 client_description client_month_bytes_uploaded=0 client_month_bytes_downloaded=0 client_total_bytes_uploaded=1024 client_total_bytes_downloaded=2048 client_base64HashClientUID=ablpaenlbidnpphgoakbeimnmclpoceciihdekma client_lastip=10.0.0.2",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	info, err := api.GetClientDBInfo(ctx, 2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.AvatarHash != "1f8e7e9bbf2bd2a7b3c0c2ba09a2e8a1" || !info.HasAvatar() || info.TotalConnections != 12 || info.LastIP != "10.0.0.2" {
+		t.Fatalf("client db info parsed incorrectly: %#v", info)
+	}
+}
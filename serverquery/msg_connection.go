@@ -0,0 +1,63 @@
+package serverquery
+
+import (
+	"context"
+	"time"
+)
+
+// ServerConnectionInfo contains the connection statistics of the virtual server.
+type ServerConnectionInfo struct {
+	ConnectionTotals
+
+	// FileTransferBytesSent is the total number of file transfer bytes sent.
+	FileTransferBytesSent int `serverquery:"connection_filetransfer_bytes_sent_total"`
+	// FileTransferBytesReceived is the total number of file transfer bytes received.
+	FileTransferBytesReceived int `serverquery:"connection_filetransfer_bytes_received_total"`
+	// ConnectedTime is the number of seconds the virtual server has been running.
+	ConnectedTime int `serverquery:"connection_connected_time"`
+	// PacketLoss is the average packet loss of all clients, from 0 to 1.
+	PacketLoss float64 `serverquery:"connection_packetloss_total"`
+	// Ping is the average ping of all clients in milliseconds.
+	Ping float64 `serverquery:"connection_ping"`
+}
+
+// ServerRequestConnectionInfoCommand requests the connection statistics of the virtual server.
+type ServerRequestConnectionInfoCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerRequestConnectionInfoCommand) GetResponseType() interface{} {
+	return &ServerConnectionInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerRequestConnectionInfoCommand) GetCommandName() string {
+	return "serverrequestconnectioninfo"
+}
+
+// GetServerConnectionInfo returns the connection statistics of the selected virtual server.
+func (c *Commands) GetServerConnectionInfo(ctx context.Context) (*ServerConnectionInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &ServerRequestConnectionInfoCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*ServerConnectionInfo), nil
+}
+
+// ClientConnectionInfo contains the connection statistics of a client.
+type ClientConnectionInfo struct {
+	ConnectionTotals
+
+	// ClientIP is the ip address of the client.
+	ClientIP string `serverquery:"connection_client_ip"`
+	// ConnectedTime is the number of milliseconds the client has been connected.
+	ConnectedTime int `serverquery:"connection_connected_time"`
+	// PacketLoss is the packet loss of the client from 0 to 1, if reported.
+	PacketLoss float64 `serverquery:"connection_packetloss_total"`
+	// Ping is the ping of the client in milliseconds, if reported.
+	Ping float64 `serverquery:"connection_ping"`
+}
+
+// ConnectedDuration returns the time the client has been connected.
+func (c *ClientConnectionInfo) ConnectedDuration() time.Duration {
+	return time.Duration(c.ConnectedTime) * time.Millisecond
+}
@@ -0,0 +1,54 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestConnectionInfo tests the server and client connection statistics.
+func TestConnectionInfo(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"serverrequestconnectioninfo": {
+			"connection_filetransfer_bandwidth_sent=0 connection_filetransfer_bandwidth_received=0 connection_filetransfer_bytes_sent_total=0 connection_filetransfer_bytes_received_total=0 connection_packets_sent_total=1000 connection_bytes_sent_total=3000000000 connection_packets_received_total=900 connection_bytes_received_total=80000 connection_bandwidth_sent_last_second_total=81 connection_bandwidth_sent_last_minute_total=92 connection_bandwidth_received_last_second_total=83 connection_bandwidth_received_last_minute_total=88 connection_connected_time=3600 connection_packetloss_total=0.0125 connection_ping=24.5",
+			"error id=0 msg=ok",
+		},
+		"clientinfo clid=5": {
+			"cid=1 client_idle_time=100 client_nickname=alice client_type=0 client_unique_identifier=alice= connection_filetransfer_bandwidth_sent=0 connection_filetransfer_bandwidth_received=0 connection_packets_sent_total=120 connection_bytes_sent_total=4096 connection_packets_received_total=110 connection_bytes_received_total=2048 connection_bandwidth_sent_last_second_total=81 connection_bandwidth_sent_last_minute_total=81 connection_bandwidth_received_last_second_total=83 connection_bandwidth_received_last_minute_total=83 connection_connected_time=90000 connection_client_ip=10.0.0.5",
+			"error id=0 msg=ok",
+		},
+		"clientlist -uid -times -voice -country -ip": {
+			"clid=5 cid=1 client_database_id=12 client_nickname=alice client_type=0 client_unique_identifier=alice= client_idle_time=2000 client_created=1600000000 client_lastconnected=1600000500 client_flag_talking=1 client_input_muted=0 client_output_muted=0 client_input_hardware=1 client_output_hardware=1 client_talk_power=75 client_is_talker=0 client_is_priority_speaker=0 client_is_recording=0 client_is_channel_commander=0 client_country=DE connection_client_ip=10.0.0.5",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	srv, err := api.GetServerConnectionInfo(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if srv.BytesSent != 3000000000 || srv.PacketLoss != 0.0125 || srv.Ping != 24.5 || srv.ConnectedTime != 3600 {
+		t.Fatalf("server connection info parsed incorrectly: %#v", srv)
+	}
+
+	info, err := api.GetClientInfo(ctx, 5)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.ClientIP != "10.0.0.5" || info.BytesReceived != 2048 || info.ConnectedDuration() != 90*time.Second {
+		t.Fatalf("client connection info parsed incorrectly: %#v", info.ClientConnectionInfo)
+	}
+
+	clients, err := api.GetClientListDetails(ctx, &ClientListDetailsCommand{Times: true, Voice: true, Country: true, IP: true})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(clients) != 1 || !clients[0].Talking || clients[0].TalkPower != 75 || clients[0].Country != "DE" || clients[0].IP != "10.0.0.5" || clients[0].LastConnected != 1600000500 {
+		t.Fatalf("client list parsed incorrectly: %#v", clients)
+	}
+	s.expectReceived(t, "serverrequestconnectioninfo", "clientinfo clid=5", "clientlist -uid -times -voice -country -ip")
+}
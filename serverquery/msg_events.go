@@ -2,6 +2,9 @@ package serverquery
 
 import (
 	"context"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // eventConstructorTable is the table of client event constructors.
@@ -19,10 +22,75 @@ type Event interface {
 	GetEventName() string
 }
 
-// UnknownEvent contains an unknown event.
+// propertiesEvent is an event that records its raw arguments.
+type propertiesEvent interface {
+	// setProperties sets the raw arguments of the event.
+	setProperties(props map[string]string)
+}
+
+// ParseEvent parses a notification line into events.
+// Notifications about several clients or channels, like
+// "notifyclientmoved ctid=2 reasonid=0 clid=5|clid=6", yield one event each.
+// Fields missing from the later entries are copied from the first entry.
+func ParseEvent(line string) ([]Event, error) {
+	msgParts := strings.SplitN(line, " ", 2)
+	msgName := msgParts[0]
+	if !strings.HasPrefix(msgName, "notify") {
+		return nil, errors.Errorf("not a notification: %s", line)
+	}
+
+	eventName := msgName[len("notify"):]
+	c, ok := eventConstructorTable[eventName]
+	if !ok {
+		return []Event{&UnknownEvent{EventSource: line}}, nil
+	}
+
+	var args string
+	if len(msgParts) > 1 {
+		args = msgParts[1]
+	}
+
+	groups := strings.Split(args, "|")
+	events := make([]Event, 0, len(groups))
+	for i, group := range groups {
+		// later entries override the fields of the first entry
+		if i != 0 {
+			group = groups[0] + " " + group
+		}
+		proto, err := UnmarshalArguments(group, c())
+		if err != nil {
+			return nil, err
+		}
+		eve := proto.(Event)
+		if pe, ok := eve.(propertiesEvent); ok {
+			pe.setProperties(splitArgumentList(group))
+		}
+		events = append(events, eve)
+	}
+	return events, nil
+}
+
+// InvokerInfo contains information about the client that caused an event.
+type InvokerInfo struct {
+	// InvokerID is the client ID of the invoker.
+	InvokerID int `serverquery:"invokerid"`
+	// InvokerName is the nickname of the invoker.
+	InvokerName string `serverquery:"invokername"`
+	// InvokerUID is the unique identifier of the invoker.
+	InvokerUID string `serverquery:"invokeruid"`
+}
+
+// GetInvokerUID returns the unique identifier of the invoker.
+func (i *InvokerInfo) GetInvokerUID() string {
+	return i.InvokerUID
+}
+
+// UnknownEvent contains an unknown event or an event that could not be decoded.
 type UnknownEvent struct {
 	// EventSource is the contents of the event.
 	EventSource string
+	// Err is the decode error, nil if the event is not known.
+	Err error
 }
 
 // GetEventName returns the event name.
@@ -43,6 +111,8 @@ type ChannelMovement struct {
 // ClientLeftView is emitted when the client leaves the view.
 type ClientLeftView struct {
 	ChannelMovement
+	// InvokerInfo is set if the client was kicked or banned.
+	InvokerInfo
 
 	// ReasonMessage is the reason why they are leaving.
 	ReasonMessage string `serverquery:"reasonmsg"`
@@ -55,6 +125,11 @@ func (c *ClientLeftView) GetEventName() string {
 	return "clientleftview"
 }
 
+// GetChannelID returns the channel the client left from.
+func (c *ClientLeftView) GetChannelID() int {
+	return c.SourceChannel
+}
+
 func init() {
 	addEventPrototype(func() Event {
 		return &ClientLeftView{}
@@ -65,6 +140,8 @@ func init() {
 type ClientEnteredView struct {
 	// ChannelMovement contains information about the channels.
 	ChannelMovement
+	// InvokerInfo is set if the client was moved by another client.
+	InvokerInfo
 	// ClientInfo will have some fields filled in.
 	ClientInfo
 }
@@ -74,6 +151,11 @@ func (c *ClientEnteredView) GetEventName() string {
 	return "cliententerview"
 }
 
+// GetChannelID returns the channel the client entered.
+func (c *ClientEnteredView) GetChannelID() int {
+	return c.TargetChannel
+}
+
 func init() {
 	addEventPrototype(func() Event {
 		return &ClientEnteredView{}
@@ -102,12 +184,266 @@ func (c *TextMessageReceived) GetEventName() string {
 	return "textmessage"
 }
 
+// GetTargetMode returns the target mode of the message.
+func (c *TextMessageReceived) GetTargetMode() int {
+	return c.TargetMode
+}
+
+// GetInvokerUID returns the unique identifier of the sender.
+func (c *TextMessageReceived) GetInvokerUID() string {
+	return c.InvokerUID
+}
+
 func init() {
 	addEventPrototype(func() Event {
 		return &TextMessageReceived{}
 	})
 }
 
+// ClientMoved is emitted when a client switches channels.
+type ClientMoved struct {
+	InvokerInfo
+
+	// TargetChannel is the channel the client went to.
+	TargetChannel int `serverquery:"ctid"`
+	// ReasonId is the reason the client moved.
+	// 0 = switched channel, 1 = moved, 4 = kicked from channel
+	ReasonId int `serverquery:"reasonid"`
+	// ClientId is the ID of the client.
+	ClientId int `serverquery:"clid"`
+}
+
+// GetEventName returns the event name.
+func (c *ClientMoved) GetEventName() string {
+	return "clientmoved"
+}
+
+// GetChannelID returns the channel the client went to.
+func (c *ClientMoved) GetChannelID() int {
+	return c.TargetChannel
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ClientMoved{}
+	})
+}
+
+// ServerEdited is emitted when the virtual server properties are edited.
+type ServerEdited struct {
+	InvokerInfo
+	// ServerBasicInfo has the edited fields filled in.
+	ServerBasicInfo
+
+	// ReasonId is the reason for the event.
+	ReasonId int `serverquery:"reasonid"`
+	// Properties contains all raw arguments of the event, including the edited properties.
+	Properties map[string]string
+}
+
+// GetEventName returns the event name.
+func (c *ServerEdited) GetEventName() string {
+	return "serveredited"
+}
+
+// setProperties sets the raw arguments of the event.
+func (c *ServerEdited) setProperties(props map[string]string) {
+	c.Properties = props
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ServerEdited{}
+	})
+}
+
+// ChannelEdited is emitted when channel properties are edited.
+type ChannelEdited struct {
+	InvokerInfo
+	// ChannelFullInfo has the channel id and the edited fields filled in.
+	ChannelFullInfo
+
+	// ReasonId is the reason for the event.
+	ReasonId int `serverquery:"reasonid"`
+	// Properties contains all raw arguments of the event, including the edited properties.
+	Properties map[string]string
+}
+
+// GetEventName returns the event name.
+func (c *ChannelEdited) GetEventName() string {
+	return "channeledited"
+}
+
+// GetChannelID returns the id of the edited channel.
+func (c *ChannelEdited) GetChannelID() int {
+	return c.Id
+}
+
+// setProperties sets the raw arguments of the event.
+func (c *ChannelEdited) setProperties(props map[string]string) {
+	c.Properties = props
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ChannelEdited{}
+	})
+}
+
+// ChannelCreated is emitted when a channel is created.
+type ChannelCreated struct {
+	InvokerInfo
+	// ChannelFullInfo contains the properties of the new channel.
+	ChannelFullInfo
+
+	// ParentChannelId is the id of the parent channel.
+	ParentChannelId int `serverquery:"cpid"`
+}
+
+// GetEventName returns the event name.
+func (c *ChannelCreated) GetEventName() string {
+	return "channelcreated"
+}
+
+// GetChannelID returns the id of the new channel.
+func (c *ChannelCreated) GetChannelID() int {
+	return c.Id
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ChannelCreated{}
+	})
+}
+
+// ChannelDeleted is emitted when a channel is deleted.
+type ChannelDeleted struct {
+	InvokerInfo
+
+	// ChannelId is the id of the deleted channel.
+	ChannelId int `serverquery:"cid"`
+}
+
+// GetEventName returns the event name.
+func (c *ChannelDeleted) GetEventName() string {
+	return "channeldeleted"
+}
+
+// GetChannelID returns the id of the deleted channel.
+func (c *ChannelDeleted) GetChannelID() int {
+	return c.ChannelId
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ChannelDeleted{}
+	})
+}
+
+// ChannelMoved is emitted when a channel is moved.
+type ChannelMoved struct {
+	InvokerInfo
+
+	// ChannelId is the id of the moved channel.
+	ChannelId int `serverquery:"cid"`
+	// ParentChannelId is the id of the new parent channel.
+	ParentChannelId int `serverquery:"cpid"`
+	// Order is the id of the channel now preceding the moved channel.
+	Order int `serverquery:"order"`
+	// ReasonId is the reason for the event.
+	ReasonId int `serverquery:"reasonid"`
+}
+
+// GetEventName returns the event name.
+func (c *ChannelMoved) GetEventName() string {
+	return "channelmoved"
+}
+
+// GetChannelID returns the id of the moved channel.
+func (c *ChannelMoved) GetChannelID() int {
+	return c.ChannelId
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ChannelMoved{}
+	})
+}
+
+// ChannelDescriptionChanged is emitted when a channel description changes.
+// The new description can be fetched with GetChannelInfo.
+type ChannelDescriptionChanged struct {
+	// ChannelId is the id of the channel.
+	ChannelId int `serverquery:"cid"`
+}
+
+// GetEventName returns the event name.
+func (c *ChannelDescriptionChanged) GetEventName() string {
+	return "channeldescriptionchanged"
+}
+
+// GetChannelID returns the id of the channel.
+func (c *ChannelDescriptionChanged) GetChannelID() int {
+	return c.ChannelId
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ChannelDescriptionChanged{}
+	})
+}
+
+// ChannelPasswordChanged is emitted when a channel password changes.
+type ChannelPasswordChanged struct {
+	// ChannelId is the id of the channel.
+	ChannelId int `serverquery:"cid"`
+}
+
+// GetEventName returns the event name.
+func (c *ChannelPasswordChanged) GetEventName() string {
+	return "channelpasswordchanged"
+}
+
+// GetChannelID returns the id of the channel.
+func (c *ChannelPasswordChanged) GetChannelID() int {
+	return c.ChannelId
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &ChannelPasswordChanged{}
+	})
+}
+
+// TokenUsed is emitted when a client uses a privilege key.
+type TokenUsed struct {
+	// ClientId is the ID of the client.
+	ClientId int `serverquery:"clid"`
+	// ClientDatabaseId is the database ID of the client.
+	ClientDatabaseId int `serverquery:"cldbid"`
+	// ClientUID is the unique identifier of the client.
+	ClientUID string `serverquery:"cluid"`
+	// Token is the privilege key that was used.
+	Token string `serverquery:"token"`
+	// TokenCustomSet is the custom set of the privilege key.
+	TokenCustomSet string `serverquery:"tokencustomset"`
+	// GroupId is the id of the group granted by the key (token1).
+	GroupId int `serverquery:"token1"`
+	// ChannelId is the id of the channel for channel group keys (token2).
+	ChannelId int `serverquery:"token2"`
+}
+
+// GetEventName returns the event name.
+func (c *TokenUsed) GetEventName() string {
+	return "tokenused"
+}
+
+func init() {
+	addEventPrototype(func() Event {
+		return &TokenUsed{}
+	})
+}
+
 // ServerNotifyRegisterCommand registers for events.
 type ServerNotifyRegisterCommand struct {
 	// EventType is one of server, channel, textserver, textchannel, textprivate
@@ -142,25 +478,29 @@ func (c *ServerNotifyRegisterWithIdCommand) GetCommandName() string {
 	return "servernotifyregister"
 }
 
+// buildServerNotifyRegisterCommand builds a register command, optionally with an id.
+func buildServerNotifyRegisterCommand(eventType string, id int) Command {
+	snr := &ServerNotifyRegisterCommand{EventType: eventType}
+	if id != 0 {
+		return &ServerNotifyRegisterWithIdCommand{
+			ServerNotifyRegisterCommand: *snr,
+			Id:                          id,
+		}
+	}
+	return snr
+}
+
 // ServerNotifyRegister registers for events, optionally with an id.
 func (c *ServerQueryAPI) ServerNotifyRegister(
 	ctx context.Context,
 	eventType string,
 	id int,
 ) error {
-	var cmd Command
-	snr := &ServerNotifyRegisterCommand{EventType: eventType}
-	if id != 0 {
-		cmd = &ServerNotifyRegisterWithIdCommand{
-			ServerNotifyRegisterCommand: *snr,
-			Id: id,
-		}
-	} else {
-		cmd = snr
+	if _, err := c.ExecuteCommand(ctx, buildServerNotifyRegisterCommand(eventType, id)); err != nil {
+		return err
 	}
-
-	_, err := c.ExecuteCommand(ctx, cmd)
-	return err
+	c.session.addNotification(eventType, id)
+	return nil
 }
 
 // ServerNotifyRegisterAll registers for all ambient events
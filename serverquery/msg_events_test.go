@@ -0,0 +1,121 @@
+package serverquery
+
+import (
+	"testing"
+)
+
+// parseSingleEvent parses a notification that yields exactly one event.
+func parseSingleEvent(t *testing.T, line string) Event {
+	t.Helper()
+	events, err := ParseEvent(line)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	return events[0]
+}
+
+// TestParseClientMoved tests parsing notifyclientmoved, including multiple clients.
+func TestParseClientMoved(t *testing.T) {
+	e := parseSingleEvent(t, "notifyclientmoved ctid=12 reasonid=1 invokerid=3 invokername=Admin\\sBob invokeruid=ABCDEF+ghijkl/mnop= clid=5").(*ClientMoved)
+	if e.TargetChannel != 12 || e.ReasonId != 1 || e.ClientId != 5 {
+		t.Fatalf("client moved parsed incorrectly: %#v", e)
+	}
+	if e.InvokerID != 3 || e.InvokerName != "Admin Bob" || e.InvokerUID != "ABCDEF+ghijkl/mnop=" {
+		t.Fatalf("invoker parsed incorrectly: %#v", e.InvokerInfo)
+	}
+
+	events, err := ParseEvent("notifyclientmoved ctid=2 reasonid=0 clid=5|clid=6")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	for i, clid := range []int{5, 6} {
+		m := events[i].(*ClientMoved)
+		if m.ClientId != clid || m.TargetChannel != 2 {
+			t.Fatalf("event %d parsed incorrectly: %#v", i, m)
+		}
+	}
+}
+
+// TestParseServerEdited tests parsing notifyserveredited.
+func TestParseServerEdited(t *testing.T) {
+	e := parseSingleEvent(t, "notifyserveredited reasonid=10 invokerid=1 invokername=serveradmin invokeruid=serveradmin virtualserver_name=My\\sServer\\s\\p\\sEU virtualserver_codec_encryption_mode=2 virtualserver_default_server_group=8 virtualserver_hostbanner_url=https:\\/\\/example.com virtualserver_priority_speaker_dimm_modificator=-18.0000 virtualserver_icon_id=0 virtualserver_name_phonetic").(*ServerEdited)
+	if e.ReasonId != 10 || e.InvokerName != "serveradmin" {
+		t.Fatalf("server edited parsed incorrectly: %#v", e)
+	}
+	if e.Name != "My Server | EU" || e.CodecEncryptionMode != 2 || e.DefaultServerGroup != 8 {
+		t.Fatalf("server properties parsed incorrectly: %#v", e.ServerBasicInfo)
+	}
+	if e.HostBannerURL != "https://example.com" || e.PrioritySpeakerDimmModificator != -18 {
+		t.Fatalf("server properties parsed incorrectly: %#v", e.ServerBasicInfo)
+	}
+	if _, ok := e.Properties["virtualserver_name_phonetic"]; !ok {
+		t.Fatalf("expected raw properties to include cleared fields: %#v", e.Properties)
+	}
+	if _, ok := e.Properties["virtualserver_hostbutton_url"]; ok {
+		t.Fatalf("expected raw properties to only include edited fields: %#v", e.Properties)
+	}
+}
+
+// TestParseChannelEvents tests parsing the channel notifications.
+func TestParseChannelEvents(t *testing.T) {
+	edited := parseSingleEvent(t, "notifychanneledited cid=5 reasonid=10 invokerid=1 invokername=serveradmin invokeruid=serveradmin channel_name=Gaming\\s\\/\\sCS channel_topic=Counter\\sStrike channel_maxclients=-1 channel_flag_maxclients_unlimited=1").(*ChannelEdited)
+	if edited.Id != 5 || edited.ReasonId != 10 || edited.Name != "Gaming / CS" || edited.Topic != "Counter Strike" {
+		t.Fatalf("channel edited parsed incorrectly: %#v", edited)
+	}
+	if edited.MaxClients != -1 || !edited.MaxClientsUnlimited || edited.Properties["channel_name"] != "Gaming / CS" {
+		t.Fatalf("channel edited parsed incorrectly: %#v", edited)
+	}
+
+	created := parseSingleEvent(t, "notifychannelcreated cid=6 cpid=5 channel_name=New\\sChannel channel_topic channel_codec=4 channel_codec_quality=6 channel_maxclients=-1 channel_maxfamilyclients=-1 channel_order=3 channel_flag_permanent=1 channel_flag_semi_permanent=0 channel_flag_default=0 channel_flag_password=0 channel_codec_latency_factor=1 channel_codec_is_unencrypted=1 channel_delete_delay=0 channel_flag_maxclients_unlimited=1 channel_flag_maxfamilyclients_unlimited=0 channel_flag_maxfamilyclients_inherited=1 channel_needed_talk_power=0 channel_name_phonetic channel_icon_id=0 invokerid=1 invokername=serveradmin invokeruid=serveradmin").(*ChannelCreated)
+	if created.Id != 6 || created.ParentChannelId != 5 || created.Name != "New Channel" || created.Order != 3 {
+		t.Fatalf("channel created parsed incorrectly: %#v", created)
+	}
+	if !created.IsPermanent || created.Codec != 4 || created.MaxClients != -1 || created.InvokerUID != "serveradmin" {
+		t.Fatalf("channel created parsed incorrectly: %#v", created)
+	}
+
+	deleted := parseSingleEvent(t, "notifychanneldeleted invokerid=1 invokername=serveradmin invokeruid=serveradmin cid=6").(*ChannelDeleted)
+	if deleted.ChannelId != 6 || deleted.InvokerID != 1 {
+		t.Fatalf("channel deleted parsed incorrectly: %#v", deleted)
+	}
+
+	moved := parseSingleEvent(t, "notifychannelmoved cid=6 cpid=2 order=4 reasonid=1 invokerid=1 invokername=serveradmin invokeruid=serveradmin").(*ChannelMoved)
+	if moved.ChannelId != 6 || moved.ParentChannelId != 2 || moved.Order != 4 || moved.ReasonId != 1 {
+		t.Fatalf("channel moved parsed incorrectly: %#v", moved)
+	}
+
+	desc := parseSingleEvent(t, "notifychanneldescriptionchanged cid=2").(*ChannelDescriptionChanged)
+	if desc.ChannelId != 2 || desc.GetChannelID() != 2 {
+		t.Fatalf("channel description changed parsed incorrectly: %#v", desc)
+	}
+
+	pass := parseSingleEvent(t, "notifychannelpasswordchanged cid=3").(*ChannelPasswordChanged)
+	if pass.ChannelId != 3 {
+		t.Fatalf("channel password changed parsed incorrectly: %#v", pass)
+	}
+}
+
+// TestParseTokenUsed tests parsing notifytokenused.
+func TestParseTokenUsed(t *testing.T) {
+	e := parseSingleEvent(t, "notifytokenused clid=7 cldbid=19 cluid=zhPQlR7UJL+R0KWfwY7hX1BiJqs= token=1ToLCSAg+uWXCVLMxgRn7ls5l2PoCjdrDbk9Sh93 tokencustomset token1=6 token2=0").(*TokenUsed)
+	if e.ClientId != 7 || e.ClientDatabaseId != 19 || e.ClientUID != "zhPQlR7UJL+R0KWfwY7hX1BiJqs=" {
+		t.Fatalf("token used parsed incorrectly: %#v", e)
+	}
+	if e.Token != "1ToLCSAg+uWXCVLMxgRn7ls5l2PoCjdrDbk9Sh93" || e.GroupId != 6 || e.ChannelId != 0 {
+		t.Fatalf("token used parsed incorrectly: %#v", e)
+	}
+}
+
+// TestParseUnknownEvent tests that unknown notifications are preserved.
+func TestParseUnknownEvent(t *testing.T) {
+	e := parseSingleEvent(t, "notifysomethingnew foo=bar").(*UnknownEvent)
+	if e.EventSource != "notifysomethingnew foo=bar" {
+		t.Fatalf("unknown event parsed incorrectly: %#v", e)
+	}
+}
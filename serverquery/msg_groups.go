@@ -2,6 +2,8 @@ package serverquery
 
 import (
 	"context"
+
+	"github.com/pkg/errors"
 )
 
 // ServerGroupSummary is the summary of a server group.
@@ -11,7 +13,7 @@ type ServerGroupSummary struct {
 	// Name is the name of the server group.
 	Name string `serverquery:"name"`
 	// Type is the type of the server group.
-	// 1 = server query, 2 = user
+	// 0 = template, 1 = regular, 2 = server query
 	Type int `serverquery:"type"`
 	// IconId is the ID of the group icon.
 	IconId int `serverquery:"iconid"`
@@ -43,7 +45,7 @@ func (c *GetServerGroupListCommand) GetCommandName() string {
 }
 
 // GetServerGroupList returns the list of server groups.
-func (c *ServerQueryAPI) GetServerGroupList(ctx context.Context) ([]*ServerGroupSummary, error) {
+func (c *Commands) GetServerGroupList(ctx context.Context) ([]*ServerGroupSummary, error) {
 	i, err := c.ExecuteCommand(ctx, &GetServerGroupListCommand{})
 	if err != nil {
 		return nil, err
@@ -54,7 +56,7 @@ func (c *ServerQueryAPI) GetServerGroupList(ctx context.Context) ([]*ServerGroup
 // ServerGroupAddClientCommand is the command to add a user to a server group.
 type ServerGroupAddClientCommand struct {
 	// ServerGroupID is the group to add
-	ServerGroupID int `servequery:"sgid"`
+	ServerGroupID int `serverquery:"sgid"`
 	// ClientDBId is the client ID in the database.
 	ClientDBId int `serverquery:"cldbid"`
 }
@@ -69,16 +71,16 @@ func (c *ServerGroupAddClientCommand) GetCommandName() string {
 	return "servergroupaddclient"
 }
 
-// GetServerGroupList returns the list of server groups.
-func (c *ServerQueryAPI) ServerGroupAddClient(ctx context.Context, clientDbId int, serverGroupId int) error {
+// ServerGroupAddClient adds a client to a server group.
+func (c *Commands) ServerGroupAddClient(ctx context.Context, clientDbId int, serverGroupId int) error {
 	_, err := c.ExecuteCommand(ctx, &ServerGroupAddClientCommand{ClientDBId: clientDbId, ServerGroupID: serverGroupId})
 	return err
 }
 
 // ServerGroupDelClientCommand is the command to delete a user from a server group.
 type ServerGroupDelClientCommand struct {
-	// ServerGroupID is the group to add
-	ServerGroupID int `servequery:"sgid"`
+	// ServerGroupID is the group to remove
+	ServerGroupID int `serverquery:"sgid"`
 	// ClientDBId is the client ID in the database.
 	ClientDBId int `serverquery:"cldbid"`
 }
@@ -93,8 +95,507 @@ func (c *ServerGroupDelClientCommand) GetCommandName() string {
 	return "servergroupdelclient"
 }
 
-// GetServerGroupList returns the list of server groups.
-func (c *ServerQueryAPI) ServerGroupDelClient(ctx context.Context, clientDbId int, serverGroupId int) error {
+// ServerGroupDelClient removes a client from a server group.
+func (c *Commands) ServerGroupDelClient(ctx context.Context, clientDbId int, serverGroupId int) error {
 	_, err := c.ExecuteCommand(ctx, &ServerGroupDelClientCommand{ClientDBId: clientDbId, ServerGroupID: serverGroupId})
 	return err
 }
+
+// Group types for servergroupadd and channelgroupadd.
+const (
+	// GroupTypeTemplate is a template group, used for new virtual servers.
+	GroupTypeTemplate = 0
+	// GroupTypeRegular is a regular group.
+	GroupTypeRegular = 1
+	// GroupTypeQuery is a server query group.
+	GroupTypeQuery = 2
+)
+
+// ServerGroupAddCommand creates a server group.
+type ServerGroupAddCommand struct {
+	// Name is the name of the server group.
+	Name string `serverquery:"name"`
+	// Type is the type of the server group.
+	Type int `serverquery:"type"`
+}
+
+// ServerGroupAddResponse is the response to servergroupadd and servergroupcopy.
+type ServerGroupAddResponse struct {
+	// ServerGroupID is the id of the new server group.
+	ServerGroupID int `serverquery:"sgid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupAddCommand) GetResponseType() interface{} {
+	return &ServerGroupAddResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupAddCommand) GetCommandName() string {
+	return "servergroupadd"
+}
+
+// AddServerGroup creates a server group, returning the new group id.
+// groupType: GroupTypeTemplate, GroupTypeRegular or GroupTypeQuery
+func (c *Commands) AddServerGroup(ctx context.Context, name string, groupType int) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &ServerGroupAddCommand{Name: name, Type: groupType})
+	if err != nil {
+		return 0, err
+	}
+	return i.(*ServerGroupAddResponse).ServerGroupID, nil
+}
+
+// ServerGroupDelCommand deletes a server group.
+type ServerGroupDelCommand struct {
+	// ServerGroupID is the group to delete.
+	ServerGroupID int `serverquery:"sgid"`
+	// Force deletes the group even if it has members.
+	Force bool `serverquery:"force"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupDelCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupDelCommand) GetCommandName() string {
+	return "servergroupdel"
+}
+
+// DeleteServerGroup deletes a server group.
+func (c *Commands) DeleteServerGroup(ctx context.Context, serverGroupId int, force bool) error {
+	_, err := c.ExecuteCommand(ctx, &ServerGroupDelCommand{ServerGroupID: serverGroupId, Force: force})
+	return err
+}
+
+// ServerGroupRenameCommand renames a server group.
+type ServerGroupRenameCommand struct {
+	// ServerGroupID is the group to rename.
+	ServerGroupID int `serverquery:"sgid"`
+	// Name is the new name of the group.
+	Name string `serverquery:"name"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupRenameCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupRenameCommand) GetCommandName() string {
+	return "servergrouprename"
+}
+
+// RenameServerGroup renames a server group.
+func (c *Commands) RenameServerGroup(ctx context.Context, serverGroupId int, name string) error {
+	_, err := c.ExecuteCommand(ctx, &ServerGroupRenameCommand{ServerGroupID: serverGroupId, Name: name})
+	return err
+}
+
+// ServerGroupCopyCommand copies a server group and its permissions.
+type ServerGroupCopyCommand struct {
+	// SourceServerGroupID is the group to copy.
+	SourceServerGroupID int `serverquery:"ssgid"`
+	// TargetServerGroupID is the group to overwrite, or 0 to create a new group.
+	TargetServerGroupID int `serverquery:"tsgid"`
+	// Name is the name of the new group, ignored when overwriting.
+	Name string `serverquery:"name"`
+	// Type is the type of the new group.
+	Type int `serverquery:"type"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupCopyCommand) GetResponseType() interface{} {
+	return &ServerGroupAddResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupCopyCommand) GetCommandName() string {
+	return "servergroupcopy"
+}
+
+// CopyServerGroup copies a server group, returning the id of the target group.
+// If targetId is 0 a new group is created with the name and type.
+func (c *Commands) CopyServerGroup(
+	ctx context.Context,
+	sourceId int,
+	targetId int,
+	name string,
+	groupType int,
+) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &ServerGroupCopyCommand{
+		SourceServerGroupID: sourceId,
+		TargetServerGroupID: targetId,
+		Name:                name,
+		Type:                groupType,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if sgid := i.(*ServerGroupAddResponse).ServerGroupID; sgid != 0 {
+		return sgid, nil
+	}
+	return targetId, nil
+}
+
+// ServerGroupClientEntry is a member of a server group.
+type ServerGroupClientEntry struct {
+	// ClientDBId is the client ID in the database.
+	ClientDBId int `serverquery:"cldbid"`
+	// Nickname is the last nickname of the client, if requested.
+	Nickname string `serverquery:"client_nickname"`
+	// UniqueIdentifier contains the client unique id, if requested.
+	UniqueIdentifier string `serverquery:"client_unique_identifier"`
+}
+
+// ServerGroupClientListCommand lists the members of a server group.
+type ServerGroupClientListCommand struct {
+	// ServerGroupID is the group to list.
+	ServerGroupID int `serverquery:"sgid"`
+	// Names requests the nickname and unique id of the members.
+	Names bool
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupClientListCommand) GetResponseType() interface{} {
+	return make([]*ServerGroupClientEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupClientListCommand) GetCommandName() string {
+	if c.Names {
+		return "servergroupclientlist -names"
+	}
+	return "servergroupclientlist"
+}
+
+// GetServerGroupClientList returns the members of a server group.
+func (c *Commands) GetServerGroupClientList(
+	ctx context.Context,
+	serverGroupId int,
+	names bool,
+) ([]*ServerGroupClientEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &ServerGroupClientListCommand{ServerGroupID: serverGroupId, Names: names})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*ServerGroupClientEntry), nil
+}
+
+// ServerGroupMembership is a server group a client is a member of.
+type ServerGroupMembership struct {
+	// ID is the server group ID.
+	ID int `serverquery:"sgid"`
+	// Name is the name of the server group.
+	Name string `serverquery:"name"`
+	// ClientDBId is the client ID in the database.
+	ClientDBId int `serverquery:"cldbid"`
+}
+
+// ServerGroupsByClientIDCommand lists the server groups of a client.
+type ServerGroupsByClientIDCommand struct {
+	// ClientDBId is the client ID in the database.
+	ClientDBId int `serverquery:"cldbid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupsByClientIDCommand) GetResponseType() interface{} {
+	return make([]*ServerGroupMembership, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupsByClientIDCommand) GetCommandName() string {
+	return "servergroupsbyclientid"
+}
+
+// GetServerGroupsByClientID returns the server groups of a client.
+func (c *Commands) GetServerGroupsByClientID(ctx context.Context, clientDbId int) ([]*ServerGroupMembership, error) {
+	i, err := c.ExecuteCommand(ctx, &ServerGroupsByClientIDCommand{ClientDBId: clientDbId})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*ServerGroupMembership), nil
+}
+
+// ServerGroupAutoAddPermCommand adds a permission to all server groups of a type.
+type ServerGroupAutoAddPermCommand struct {
+	// GroupType is the i_group_auto_update_type value of the groups, like 40 for server admins.
+	GroupType int `serverquery:"sgtype"`
+	// PermissionName is the name of the permission, like i_client_talk_power.
+	PermissionName string `serverquery:"permsid"`
+	// Value is the permission value.
+	Value int `serverquery:"permvalue"`
+	// Negated is set to negate the permission.
+	Negated bool `serverquery:"permnegated"`
+	// Skip is set to skip the permission in channel groups and client permissions.
+	Skip bool `serverquery:"permskip"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerGroupAutoAddPermCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerGroupAutoAddPermCommand) GetCommandName() string {
+	return "servergroupautoaddperm"
+}
+
+// ServerGroupAutoAddPerm adds a permission to all server groups of a type.
+func (c *Commands) ServerGroupAutoAddPerm(ctx context.Context, cmd *ServerGroupAutoAddPermCommand) error {
+	_, err := c.ExecuteCommand(ctx, cmd)
+	return err
+}
+
+// ChannelGroupSummary is the summary of a channel group.
+type ChannelGroupSummary struct {
+	// ID is the channel group ID.
+	ID int `serverquery:"cgid"`
+	// Name is the name of the channel group.
+	Name string `serverquery:"name"`
+	// Type is the type of the channel group.
+	// 0 = template, 1 = regular, 2 = server query
+	Type int `serverquery:"type"`
+	// IconId is the ID of the group icon.
+	IconId int `serverquery:"iconid"`
+	// SaveDb marks if the channel group can save to the database.
+	SaveDb bool `serverquery:"savedb"`
+	// SortId ???
+	SortId int `serverquery:"sortid"`
+	// NameMode ???
+	NameMode int `serverquery:"namemode"`
+	// ModifyPower is the modify power of the group.
+	ModifyPower int `serverquery:"n_modifyp"`
+	// MemberAddPower is the add power of the group.
+	MemberAddPower int `serverquery:"n_member_addp"`
+	// MemberRemovePower is the member remove power of the group.
+	MemberRemovePower int `serverquery:"n_member_removep"`
+}
+
+// GetChannelGroupListCommand requests the channel group list.
+type GetChannelGroupListCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *GetChannelGroupListCommand) GetResponseType() interface{} {
+	return make([]*ChannelGroupSummary, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *GetChannelGroupListCommand) GetCommandName() string {
+	return "channelgrouplist"
+}
+
+// GetChannelGroupList returns the list of channel groups.
+func (c *Commands) GetChannelGroupList(ctx context.Context) ([]*ChannelGroupSummary, error) {
+	i, err := c.ExecuteCommand(ctx, &GetChannelGroupListCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*ChannelGroupSummary), nil
+}
+
+// ChannelGroupAddCommand creates a channel group.
+type ChannelGroupAddCommand struct {
+	// Name is the name of the channel group.
+	Name string `serverquery:"name"`
+	// Type is the type of the channel group.
+	Type int `serverquery:"type"`
+}
+
+// ChannelGroupAddResponse is the response to channelgroupadd and channelgroupcopy.
+type ChannelGroupAddResponse struct {
+	// ChannelGroupID is the id of the new channel group.
+	ChannelGroupID int `serverquery:"cgid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelGroupAddCommand) GetResponseType() interface{} {
+	return &ChannelGroupAddResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelGroupAddCommand) GetCommandName() string {
+	return "channelgroupadd"
+}
+
+// AddChannelGroup creates a channel group, returning the new group id.
+// groupType: GroupTypeTemplate, GroupTypeRegular or GroupTypeQuery
+func (c *Commands) AddChannelGroup(ctx context.Context, name string, groupType int) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &ChannelGroupAddCommand{Name: name, Type: groupType})
+	if err != nil {
+		return 0, err
+	}
+	return i.(*ChannelGroupAddResponse).ChannelGroupID, nil
+}
+
+// ChannelGroupDelCommand deletes a channel group.
+type ChannelGroupDelCommand struct {
+	// ChannelGroupID is the group to delete.
+	ChannelGroupID int `serverquery:"cgid"`
+	// Force deletes the group even if it has members.
+	Force bool `serverquery:"force"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelGroupDelCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelGroupDelCommand) GetCommandName() string {
+	return "channelgroupdel"
+}
+
+// DeleteChannelGroup deletes a channel group.
+func (c *Commands) DeleteChannelGroup(ctx context.Context, channelGroupId int, force bool) error {
+	_, err := c.ExecuteCommand(ctx, &ChannelGroupDelCommand{ChannelGroupID: channelGroupId, Force: force})
+	return err
+}
+
+// ChannelGroupRenameCommand renames a channel group.
+type ChannelGroupRenameCommand struct {
+	// ChannelGroupID is the group to rename.
+	ChannelGroupID int `serverquery:"cgid"`
+	// Name is the new name of the group.
+	Name string `serverquery:"name"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelGroupRenameCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelGroupRenameCommand) GetCommandName() string {
+	return "channelgrouprename"
+}
+
+// RenameChannelGroup renames a channel group.
+func (c *Commands) RenameChannelGroup(ctx context.Context, channelGroupId int, name string) error {
+	_, err := c.ExecuteCommand(ctx, &ChannelGroupRenameCommand{ChannelGroupID: channelGroupId, Name: name})
+	return err
+}
+
+// ChannelGroupCopyCommand copies a channel group and its permissions.
+type ChannelGroupCopyCommand struct {
+	// SourceChannelGroupID is the group to copy.
+	SourceChannelGroupID int `serverquery:"scgid"`
+	// TargetChannelGroupID is the group to overwrite, or 0 to create a new group.
+	TargetChannelGroupID int `serverquery:"tcgid"`
+	// Name is the name of the new group, ignored when overwriting.
+	Name string `serverquery:"name"`
+	// Type is the type of the new group.
+	Type int `serverquery:"type"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelGroupCopyCommand) GetResponseType() interface{} {
+	return &ChannelGroupAddResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelGroupCopyCommand) GetCommandName() string {
+	return "channelgroupcopy"
+}
+
+// CopyChannelGroup copies a channel group, returning the id of the target group.
+// If targetId is 0 a new group is created with the name and type.
+func (c *Commands) CopyChannelGroup(
+	ctx context.Context,
+	sourceId int,
+	targetId int,
+	name string,
+	groupType int,
+) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &ChannelGroupCopyCommand{
+		SourceChannelGroupID: sourceId,
+		TargetChannelGroupID: targetId,
+		Name:                 name,
+		Type:                 groupType,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if cgid := i.(*ChannelGroupAddResponse).ChannelGroupID; cgid != 0 {
+		return cgid, nil
+	}
+	return targetId, nil
+}
+
+// ChannelGroupClientEntry is a channel group assignment.
+type ChannelGroupClientEntry struct {
+	// ChannelId is the channel of the assignment.
+	ChannelId int `serverquery:"cid"`
+	// ClientDBId is the client ID in the database.
+	ClientDBId int `serverquery:"cldbid"`
+	// ChannelGroupID is the channel group ID.
+	ChannelGroupID int `serverquery:"cgid"`
+}
+
+// ChannelGroupClientListCommand lists channel group assignments.
+// Zero fields are not used as filters.
+type ChannelGroupClientListCommand struct {
+	// ChannelId filters by channel.
+	ChannelId int `serverquery:"cid,omitempty"`
+	// ClientDBId filters by client database ID.
+	ClientDBId int `serverquery:"cldbid,omitempty"`
+	// ChannelGroupID filters by channel group.
+	ChannelGroupID int `serverquery:"cgid,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ChannelGroupClientListCommand) GetResponseType() interface{} {
+	return make([]*ChannelGroupClientEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *ChannelGroupClientListCommand) GetCommandName() string {
+	return "channelgroupclientlist"
+}
+
+// GetChannelGroupClientList returns the channel group assignments matching the filter.
+// Returns an empty list if nothing matches.
+func (c *Commands) GetChannelGroupClientList(
+	ctx context.Context,
+	filter *ChannelGroupClientListCommand,
+) ([]*ChannelGroupClientEntry, error) {
+	i, err := c.ExecuteCommand(ctx, filter)
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]*ChannelGroupClientEntry, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*ChannelGroupClientEntry), nil
+}
+
+// SetClientChannelGroupCommand sets the channel group of a client in a channel.
+type SetClientChannelGroupCommand struct {
+	// ChannelGroupID is the channel group ID.
+	ChannelGroupID int `serverquery:"cgid"`
+	// ChannelId is the channel.
+	ChannelId int `serverquery:"cid"`
+	// ClientDBId is the client ID in the database.
+	ClientDBId int `serverquery:"cldbid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *SetClientChannelGroupCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *SetClientChannelGroupCommand) GetCommandName() string {
+	return "setclientchannelgroup"
+}
+
+// SetClientChannelGroup sets the channel group of a client in a channel.
+func (c *Commands) SetClientChannelGroup(ctx context.Context, channelGroupId, channelId, clientDbId int) error {
+	_, err := c.ExecuteCommand(ctx, &SetClientChannelGroupCommand{
+		ChannelGroupID: channelGroupId,
+		ChannelId:      channelId,
+		ClientDBId:     clientDbId,
+	})
+	return err
+}
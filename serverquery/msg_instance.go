@@ -0,0 +1,303 @@
+package serverquery
+
+import (
+	"context"
+	"time"
+)
+
+// VersionInfo is the response to the version command.
+type VersionInfo struct {
+	// Version is the server version.
+	Version string `serverquery:"version"`
+	// Build is the build number of the server.
+	Build int `serverquery:"build"`
+	// Platform is the platform the server is running on.
+	Platform string `serverquery:"platform"`
+}
+
+// VersionCommand requests the server version.
+type VersionCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *VersionCommand) GetResponseType() interface{} {
+	return &VersionInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *VersionCommand) GetCommandName() string {
+	return "version"
+}
+
+// GetVersion returns the server version.
+func (c *Commands) GetVersion(ctx context.Context) (*VersionInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &VersionCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*VersionInfo), nil
+}
+
+// WhoAmI is information about the query client session.
+type WhoAmI struct {
+	// ServerStatus is the status of the selected virtual server, or unknown.
+	ServerStatus string `serverquery:"virtualserver_status"`
+	// ServerID is the id of the selected virtual server, 0 if none.
+	ServerID int `serverquery:"virtualserver_id"`
+	// ServerUniqueIdentifier is the unique id of the selected virtual server.
+	ServerUniqueIdentifier string `serverquery:"virtualserver_unique_identifier"`
+	// ServerPort is the port of the selected virtual server.
+	ServerPort int `serverquery:"virtualserver_port"`
+	// ClientId is the client id of the query client.
+	ClientId int `serverquery:"client_id"`
+	// ChannelId is the channel the query client is in.
+	ChannelId int `serverquery:"client_channel_id"`
+	// Nickname is the nickname of the query client.
+	Nickname string `serverquery:"client_nickname"`
+	// DatabaseId is the database id of the logged in account.
+	DatabaseId int `serverquery:"client_database_id"`
+	// LoginName is the login name of the logged in account.
+	LoginName string `serverquery:"client_login_name"`
+	// UniqueIdentifier is the unique id of the logged in account.
+	UniqueIdentifier string `serverquery:"client_unique_identifier"`
+	// OriginServerID is the id of the server the query client was created on.
+	OriginServerID int `serverquery:"client_origin_server_id"`
+}
+
+// WhoAmICommand requests information about the query client session.
+type WhoAmICommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *WhoAmICommand) GetResponseType() interface{} {
+	return &WhoAmI{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *WhoAmICommand) GetCommandName() string {
+	return "whoami"
+}
+
+// WhoAmI returns information about the query client session.
+func (c *Commands) WhoAmI(ctx context.Context) (*WhoAmI, error) {
+	i, err := c.ExecuteCommand(ctx, &WhoAmICommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*WhoAmI), nil
+}
+
+// ConnectionTotals are the bandwidth and packet counters of a connection.
+type ConnectionTotals struct {
+	// FileTransferBandwidthSent is the current file transfer upload in bytes per second.
+	FileTransferBandwidthSent int `serverquery:"connection_filetransfer_bandwidth_sent"`
+	// FileTransferBandwidthReceived is the current file transfer download in bytes per second.
+	FileTransferBandwidthReceived int `serverquery:"connection_filetransfer_bandwidth_received"`
+	// PacketsSent is the total number of packets sent.
+	PacketsSent int `serverquery:"connection_packets_sent_total"`
+	// BytesSent is the total number of bytes sent.
+	BytesSent int `serverquery:"connection_bytes_sent_total"`
+	// PacketsReceived is the total number of packets received.
+	PacketsReceived int `serverquery:"connection_packets_received_total"`
+	// BytesReceived is the total number of bytes received.
+	BytesReceived int `serverquery:"connection_bytes_received_total"`
+	// BandwidthSentLastSecond is the bytes per second sent over the last second.
+	BandwidthSentLastSecond int `serverquery:"connection_bandwidth_sent_last_second_total"`
+	// BandwidthSentLastMinute is the bytes per second sent over the last minute.
+	BandwidthSentLastMinute int `serverquery:"connection_bandwidth_sent_last_minute_total"`
+	// BandwidthReceivedLastSecond is the bytes per second received over the last second.
+	BandwidthReceivedLastSecond int `serverquery:"connection_bandwidth_received_last_second_total"`
+	// BandwidthReceivedLastMinute is the bytes per second received over the last minute.
+	BandwidthReceivedLastMinute int `serverquery:"connection_bandwidth_received_last_minute_total"`
+}
+
+// HostInfo is the response to the hostinfo command.
+type HostInfo struct {
+	ConnectionTotals
+
+	// Uptime is the uptime of the server instance in seconds.
+	Uptime int `serverquery:"instance_uptime"`
+	// Timestamp is the current unix timestamp of the host.
+	Timestamp int `serverquery:"host_timestamp_utc"`
+	// ServersRunning is the number of running virtual servers.
+	ServersRunning int `serverquery:"virtualservers_running_total"`
+	// MaxClients is the sum of the max clients of the running virtual servers.
+	MaxClients int `serverquery:"virtualservers_total_maxclients"`
+	// ClientsOnline is the number of clients online on all virtual servers.
+	ClientsOnline int `serverquery:"virtualservers_total_clients_online"`
+	// ChannelsOnline is the number of channels on all virtual servers.
+	ChannelsOnline int `serverquery:"virtualservers_total_channels_online"`
+	// FileTransferBytesSent is the total number of file transfer bytes sent.
+	FileTransferBytesSent int `serverquery:"connection_filetransfer_bytes_sent_total"`
+	// FileTransferBytesReceived is the total number of file transfer bytes received.
+	FileTransferBytesReceived int `serverquery:"connection_filetransfer_bytes_received_total"`
+}
+
+// UptimeDuration returns the uptime of the server instance.
+func (h *HostInfo) UptimeDuration() time.Duration {
+	return time.Duration(h.Uptime) * time.Second
+}
+
+// HostInfoCommand requests information about the host.
+type HostInfoCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *HostInfoCommand) GetResponseType() interface{} {
+	return &HostInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *HostInfoCommand) GetCommandName() string {
+	return "hostinfo"
+}
+
+// GetHostInfo returns information about the host, like uptime and bandwidth totals.
+func (c *Commands) GetHostInfo(ctx context.Context) (*HostInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &HostInfoCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*HostInfo), nil
+}
+
+// InstanceInfo is the response to the instanceinfo command.
+type InstanceInfo struct {
+	// DatabaseVersion is the version of the database schema.
+	DatabaseVersion int `serverquery:"serverinstance_database_version"`
+	// FileTransferPort is the port of the file transfer service.
+	FileTransferPort int `serverquery:"serverinstance_filetransfer_port"`
+	// GuestServerQueryGroup is the server group of unauthenticated query clients.
+	GuestServerQueryGroup int `serverquery:"serverinstance_guest_serverquery_group"`
+	// FloodCommands is the number of commands allowed within FloodTime.
+	FloodCommands int `serverquery:"serverinstance_serverquery_flood_commands"`
+	// FloodTime is the flood protection window in seconds.
+	FloodTime int `serverquery:"serverinstance_serverquery_flood_time"`
+	// FloodBanTime is the length of flood bans in seconds.
+	FloodBanTime int `serverquery:"serverinstance_serverquery_ban_time"`
+	// TemplateServerAdminGroup is the server admin group template.
+	TemplateServerAdminGroup int `serverquery:"serverinstance_template_serveradmin_group"`
+	// TemplateServerDefaultGroup is the default server group template.
+	TemplateServerDefaultGroup int `serverquery:"serverinstance_template_serverdefault_group"`
+	// TemplateChannelAdminGroup is the channel admin group template.
+	TemplateChannelAdminGroup int `serverquery:"serverinstance_template_channeladmin_group"`
+	// TemplateChannelDefaultGroup is the default channel group template.
+	TemplateChannelDefaultGroup int `serverquery:"serverinstance_template_channeldefault_group"`
+	// PermissionsVersion is the version of the permission system.
+	PermissionsVersion int `serverquery:"serverinstance_permissions_version"`
+	// PendingConnectionsPerIP is the number of pending connections allowed per ip.
+	PendingConnectionsPerIP int `serverquery:"serverinstance_pending_connections_per_ip"`
+}
+
+// InstanceInfoCommand requests the server instance properties.
+type InstanceInfoCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *InstanceInfoCommand) GetResponseType() interface{} {
+	return &InstanceInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *InstanceInfoCommand) GetCommandName() string {
+	return "instanceinfo"
+}
+
+// GetInstanceInfo returns the server instance properties.
+func (c *Commands) GetInstanceInfo(ctx context.Context) (*InstanceInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &InstanceInfoCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*InstanceInfo), nil
+}
+
+// InstanceProperties are the editable server instance properties.
+// Nil fields are left unchanged.
+type InstanceProperties struct {
+	// GuestServerQueryGroup is the server group of unauthenticated query clients.
+	GuestServerQueryGroup *int `serverquery:"serverinstance_guest_serverquery_group,omitempty"`
+	// FloodCommands is the number of commands allowed within FloodTime.
+	FloodCommands *int `serverquery:"serverinstance_serverquery_flood_commands,omitempty"`
+	// FloodTime is the flood protection window in seconds.
+	FloodTime *int `serverquery:"serverinstance_serverquery_flood_time,omitempty"`
+	// FloodBanTime is the length of flood bans in seconds.
+	FloodBanTime *int `serverquery:"serverinstance_serverquery_ban_time,omitempty"`
+	// TemplateServerAdminGroup is the server admin group template.
+	TemplateServerAdminGroup *int `serverquery:"serverinstance_template_serveradmin_group,omitempty"`
+	// TemplateServerDefaultGroup is the default server group template.
+	TemplateServerDefaultGroup *int `serverquery:"serverinstance_template_serverdefault_group,omitempty"`
+	// TemplateChannelAdminGroup is the channel admin group template.
+	TemplateChannelAdminGroup *int `serverquery:"serverinstance_template_channeladmin_group,omitempty"`
+	// TemplateChannelDefaultGroup is the default channel group template.
+	TemplateChannelDefaultGroup *int `serverquery:"serverinstance_template_channeldefault_group,omitempty"`
+	// FileTransferPort is the port of the file transfer service.
+	FileTransferPort *int `serverquery:"serverinstance_filetransfer_port,omitempty"`
+	// PendingConnectionsPerIP is the number of pending connections allowed per ip.
+	PendingConnectionsPerIP *int `serverquery:"serverinstance_pending_connections_per_ip,omitempty"`
+}
+
+// InstanceEditCommand edits the server instance properties.
+type InstanceEditCommand struct {
+	InstanceProperties
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *InstanceEditCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *InstanceEditCommand) GetCommandName() string {
+	return "instanceedit"
+}
+
+// EditInstance edits the server instance properties, like the query flood settings.
+func (c *Commands) EditInstance(ctx context.Context, props InstanceProperties) error {
+	_, err := c.ExecuteCommand(ctx, &InstanceEditCommand{InstanceProperties: props})
+	return err
+}
+
+// Binding subsystems for bindinglist.
+const (
+	// BindingSubsystemVoice lists the voice bindings.
+	BindingSubsystemVoice = "voice"
+	// BindingSubsystemQuery lists the query bindings.
+	BindingSubsystemQuery = "query"
+	// BindingSubsystemFileTransfer lists the file transfer bindings.
+	BindingSubsystemFileTransfer = "filetransfer"
+)
+
+// BindingEntry is an entry in the binding list.
+type BindingEntry struct {
+	// IP is the bound ip address.
+	IP string `serverquery:"ip"`
+}
+
+// BindingListCommand lists the ip addresses the server is bound to.
+type BindingListCommand struct {
+	// Subsystem is one of the BindingSubsystem constants, voice if empty.
+	Subsystem string `serverquery:"subsystem,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *BindingListCommand) GetResponseType() interface{} {
+	return make([]*BindingEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *BindingListCommand) GetCommandName() string {
+	return "bindinglist"
+}
+
+// GetBindingList returns the ip addresses the subsystem is bound to.
+// The subsystem is one of the BindingSubsystem constants, or empty for voice.
+func (c *Commands) GetBindingList(ctx context.Context, subsystem string) ([]string, error) {
+	i, err := c.ExecuteCommand(ctx, &BindingListCommand{Subsystem: subsystem})
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := i.([]*BindingEntry)
+	res := make([]string, len(bindings))
+	for idx, binding := range bindings {
+		res[idx] = binding.IP
+	}
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestInstanceInfo tests the instance and host information commands.
+func TestInstanceInfo(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"hostinfo": {
+			"instance_uptime=86400 host_timestamp_utc=1600000000 virtualservers_running_total=2 virtualservers_total_maxclients=64 virtualservers_total_clients_online=5 virtualservers_total_channels_online=12 connection_filetransfer_bandwidth_sent=0 connection_filetransfer_bandwidth_received=0 connection_filetransfer_bytes_sent_total=0 connection_filetransfer_bytes_received_total=0 connection_packets_sent_total=1234 connection_bytes_sent_total=5000000000 connection_packets_received_total=4321 connection_bytes_received_total=1000 connection_bandwidth_sent_last_second_total=81 connection_bandwidth_sent_last_minute_total=92 connection_bandwidth_received_last_second_total=83 connection_bandwidth_received_last_minute_total=88",
+			"error id=0 msg=ok",
+		},
+		"instanceinfo": {
+			"serverinstance_database_version=26 serverinstance_filetransfer_port=30033 serverinstance_max_download_total_bandwidth=18446744073709551615 serverinstance_max_upload_total_bandwidth=18446744073709551615 serverinstance_guest_serverquery_group=1 serverinstance_serverquery_flood_commands=10 serverinstance_serverquery_flood_time=3 serverinstance_serverquery_ban_time=600 serverinstance_template_serveradmin_group=3 serverinstance_template_serverdefault_group=5 serverinstance_template_channeladmin_group=1 serverinstance_template_channeldefault_group=4 serverinstance_permissions_version=19 serverinstance_pending_connections_per_ip=0",
+			"error id=0 msg=ok",
+		},
+		"instanceedit serverinstance_serverquery_flood_commands=50 serverinstance_serverquery_flood_time=3": {"error id=0 msg=ok"},
+		"version": {
+			"version=3.13.7 build=1655727713 platform=Linux",
+			"error id=0 msg=ok",
+		},
+		"bindinglist subsystem=query": {
+			"ip=0.0.0.0|ip=::",
+			"error id=0 msg=ok",
+		},
+		"whoami": {
+			"virtualserver_status=online virtualserver_id=1 virtualserver_unique_identifier=abc= virtualserver_port=9987 client_id=3 client_channel_id=1 client_nickname=serveradmin client_database_id=1 client_login_name=serveradmin client_unique_identifier=serveradmin client_origin_server_id=0",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	host, err := api.GetHostInfo(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if host.UptimeDuration() != 24*time.Hour || host.ServersRunning != 2 || host.ClientsOnline != 5 || host.BytesSent != 5000000000 {
+		t.Fatalf("host info parsed incorrectly: %#v", host)
+	}
+
+	instance, err := api.GetInstanceInfo(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if instance.FloodCommands != 10 || instance.FloodTime != 3 || instance.FloodBanTime != 600 || instance.FileTransferPort != 30033 || instance.TemplateServerDefaultGroup != 5 {
+		t.Fatalf("instance info parsed incorrectly: %#v", instance)
+	}
+
+	floodCommands, floodTime := 50, 3
+	if err := api.EditInstance(ctx, InstanceProperties{FloodCommands: &floodCommands, FloodTime: &floodTime}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ips, err := api.GetBindingList(ctx, BindingSubsystemQuery)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(ips) != 2 || ips[0] != "0.0.0.0" || ips[1] != "::" {
+		t.Fatalf("expected bindings [0.0.0.0 ::], got %v", ips)
+	}
+
+	version, err := api.GetVersion(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if version.Version != "3.13.7" || version.Build != 1655727713 || version.Platform != "Linux" {
+		t.Fatalf("version parsed incorrectly: %#v", version)
+	}
+
+	me, err := api.WhoAmI(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if me.ServerID != 1 || me.ClientId != 3 || me.LoginName != "serveradmin" {
+		t.Fatalf("whoami parsed incorrectly: %#v", me)
+	}
+	s.expectReceived(t,
+		"hostinfo",
+		"instanceinfo",
+		"instanceedit serverinstance_serverquery_flood_commands=50 serverinstance_serverquery_flood_time=3",
+		"bindinglist subsystem=query",
+		"version",
+		"whoami",
+	)
+}
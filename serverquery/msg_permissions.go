@@ -0,0 +1,451 @@
+package serverquery
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// PermissionRef refers to a permission by id or by name.
+// Only one of the fields needs to be set.
+type PermissionRef struct {
+	// Id is the id of the permission.
+	Id int `serverquery:"permid,omitempty"`
+	// Name is the name of the permission, like i_client_talk_power.
+	Name string `serverquery:"permsid,omitempty"`
+}
+
+// Permission is a permission value.
+type Permission struct {
+	PermissionRef
+
+	// Value is the permission value.
+	Value int `serverquery:"permvalue"`
+	// Negated is set if the permission is negated.
+	Negated bool `serverquery:"permnegated,omitempty"`
+	// Skip is set if the permission skips channel group and client permissions.
+	Skip bool `serverquery:"permskip,omitempty"`
+}
+
+// PermissionInfo describes a permission known to the server.
+type PermissionInfo struct {
+	// Id is the id of the permission.
+	Id int `serverquery:"permid"`
+	// Name is the name of the permission.
+	Name string `serverquery:"permname"`
+	// Description is the description of the permission.
+	Description string `serverquery:"permdesc"`
+}
+
+// PermissionListCommand lists the permissions known to the server.
+type PermissionListCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *PermissionListCommand) GetResponseType() interface{} {
+	return make([]*PermissionInfo, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *PermissionListCommand) GetCommandName() string {
+	return "permissionlist"
+}
+
+// GetPermissionList returns the permissions known to the server.
+func (c *Commands) GetPermissionList(ctx context.Context) ([]*PermissionInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &PermissionListCommand{})
+	if err != nil {
+		return nil, err
+	}
+
+	// newer servers interleave permission group markers without a name
+	list := i.([]*PermissionInfo)
+	res := make([]*PermissionInfo, 0, len(list))
+	for _, perm := range list {
+		if perm.Name != "" {
+			res = append(res, perm)
+		}
+	}
+	return res, nil
+}
+
+// PermIdGetByNameCommand looks up permission ids by name.
+type PermIdGetByNameCommand struct {
+	// Names are the permission names.
+	Names []string `serverquery:"permsid,pipe"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PermIdGetByNameCommand) GetResponseType() interface{} {
+	return make([]*PermissionRef, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *PermIdGetByNameCommand) GetCommandName() string {
+	return "permidgetbyname"
+}
+
+// GetPermissionIdsByName returns the ids and names of the permissions.
+func (c *Commands) GetPermissionIdsByName(ctx context.Context, names ...string) ([]*PermissionRef, error) {
+	i, err := c.ExecuteCommand(ctx, &PermIdGetByNameCommand{Names: names})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*PermissionRef), nil
+}
+
+// Permission assignment types in permfind and permoverview results.
+const (
+	// PermTypeServerGroup is a server group permission, id1 is the sgid.
+	PermTypeServerGroup = 0
+	// PermTypeClient is a client permission, id1 is the cldbid.
+	PermTypeClient = 1
+	// PermTypeChannel is a channel permission, id2 is the cid.
+	PermTypeChannel = 2
+	// PermTypeChannelGroup is a channel group permission, id1 is the cgid and id2 the cid.
+	PermTypeChannelGroup = 3
+	// PermTypeChannelClient is a channel client permission, id1 is the cldbid and id2 the cid.
+	PermTypeChannelClient = 4
+)
+
+// PermFindEntry is a permission assignment found by permfind.
+type PermFindEntry struct {
+	// Type is the assignment type, like PermTypeServerGroup.
+	Type int `serverquery:"t"`
+	// Id1 is the first id of the assignment.
+	Id1 int `serverquery:"id1"`
+	// Id2 is the second id of the assignment.
+	Id2 int `serverquery:"id2"`
+	// PermissionId is the id of the permission.
+	PermissionId int `serverquery:"p"`
+}
+
+// PermFindCommand finds the assignments of a permission.
+type PermFindCommand struct {
+	PermissionRef
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PermFindCommand) GetResponseType() interface{} {
+	return make([]*PermFindEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *PermFindCommand) GetCommandName() string {
+	return "permfind"
+}
+
+// FindPermission returns the assignments of a permission.
+// Returns an empty list if the permission is not assigned.
+func (c *Commands) FindPermission(ctx context.Context, perm PermissionRef) ([]*PermFindEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &PermFindCommand{PermissionRef: perm})
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]*PermFindEntry, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*PermFindEntry), nil
+}
+
+// PermOverviewEntry is a permission assignment affecting a client in a channel.
+type PermOverviewEntry struct {
+	// Type is the assignment type, like PermTypeServerGroup.
+	Type int `serverquery:"t"`
+	// Id1 is the first id of the assignment.
+	Id1 int `serverquery:"id1"`
+	// Id2 is the second id of the assignment.
+	Id2 int `serverquery:"id2"`
+	// PermissionId is the id of the permission.
+	PermissionId int `serverquery:"p"`
+	// Value is the permission value.
+	Value int `serverquery:"v"`
+	// Negated is set if the permission is negated.
+	Negated bool `serverquery:"n"`
+	// Skip is set if the permission is skipped.
+	Skip bool `serverquery:"s"`
+}
+
+// PermOverviewCommand lists the permissions affecting a client in a channel.
+type PermOverviewCommand struct {
+	// ChannelId is the channel.
+	ChannelId int `serverquery:"cid"`
+	// ClientDBId is the client ID in the database.
+	ClientDBId int `serverquery:"cldbid"`
+	// PermissionId limits the overview to a permission, or 0 for all.
+	PermissionId int `serverquery:"permid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PermOverviewCommand) GetResponseType() interface{} {
+	return make([]*PermOverviewEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *PermOverviewCommand) GetCommandName() string {
+	return "permoverview"
+}
+
+// GetPermissionOverview returns the permissions affecting a client in a channel.
+// If permissionId is 0 all permissions are listed.
+func (c *Commands) GetPermissionOverview(
+	ctx context.Context,
+	channelId int,
+	clientDbId int,
+	permissionId int,
+) ([]*PermOverviewEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &PermOverviewCommand{
+		ChannelId:    channelId,
+		ClientDBId:   clientDbId,
+		PermissionId: permissionId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*PermOverviewEntry), nil
+}
+
+// PermGetCommand gets the value of a permission for the query client.
+type PermGetCommand struct {
+	PermissionRef
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PermGetCommand) GetResponseType() interface{} {
+	return &Permission{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *PermGetCommand) GetCommandName() string {
+	return "permget"
+}
+
+// GetPermission returns the value of a permission for the query client.
+func (c *Commands) GetPermission(ctx context.Context, perm PermissionRef) (*Permission, error) {
+	i, err := c.ExecuteCommand(ctx, &PermGetCommand{PermissionRef: perm})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*Permission), nil
+}
+
+// PermissionScope selects what permissions are assigned to.
+// Build it with one of the ServerGroupPermissions style constructors.
+type PermissionScope struct {
+	// commandPrefix is the command name prefix, like servergroup.
+	commandPrefix string
+
+	// ServerGroupID is the server group, for server group permissions.
+	ServerGroupID int `serverquery:"sgid,omitempty"`
+	// ChannelGroupID is the channel group, for channel group permissions.
+	ChannelGroupID int `serverquery:"cgid,omitempty"`
+	// ChannelId is the channel, for channel and channel client permissions.
+	ChannelId int `serverquery:"cid,omitempty"`
+	// ClientDBId is the client, for client and channel client permissions.
+	ClientDBId int `serverquery:"cldbid,omitempty"`
+}
+
+// ServerGroupPermissions is the scope of the permissions of a server group.
+func ServerGroupPermissions(serverGroupId int) PermissionScope {
+	return PermissionScope{commandPrefix: "servergroup", ServerGroupID: serverGroupId}
+}
+
+// ChannelGroupPermissions is the scope of the permissions of a channel group.
+func ChannelGroupPermissions(channelGroupId int) PermissionScope {
+	return PermissionScope{commandPrefix: "channelgroup", ChannelGroupID: channelGroupId}
+}
+
+// ChannelPermissions is the scope of the permissions of a channel.
+func ChannelPermissions(channelId int) PermissionScope {
+	return PermissionScope{commandPrefix: "channel", ChannelId: channelId}
+}
+
+// ClientPermissions is the scope of the permissions of a client.
+func ClientPermissions(clientDbId int) PermissionScope {
+	return PermissionScope{commandPrefix: "client", ClientDBId: clientDbId}
+}
+
+// ChannelClientPermissions is the scope of the permissions of a client in a channel.
+func ChannelClientPermissions(channelId, clientDbId int) PermissionScope {
+	return PermissionScope{commandPrefix: "channelclient", ChannelId: channelId, ClientDBId: clientDbId}
+}
+
+// AddPermCommand adds or updates permissions, like servergroupaddperm.
+type AddPermCommand struct {
+	PermissionScope
+
+	// Permissions are the permissions to set.
+	// Negated and Skip are only supported by some scopes.
+	Permissions []*Permission `serverquery:",pipe"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *AddPermCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *AddPermCommand) GetCommandName() string {
+	return c.commandPrefix + "addperm"
+}
+
+// AddPermissions adds or updates permissions in a scope.
+func (c *Commands) AddPermissions(ctx context.Context, scope PermissionScope, perms ...*Permission) error {
+	_, err := c.ExecuteCommand(ctx, &AddPermCommand{PermissionScope: scope, Permissions: perms})
+	return err
+}
+
+// DelPermCommand removes permissions, like servergroupdelperm.
+type DelPermCommand struct {
+	PermissionScope
+
+	// Permissions are the permissions to remove.
+	Permissions []*PermissionRef `serverquery:",pipe"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *DelPermCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *DelPermCommand) GetCommandName() string {
+	return c.commandPrefix + "delperm"
+}
+
+// DeletePermissions removes permissions from a scope.
+func (c *Commands) DeletePermissions(ctx context.Context, scope PermissionScope, perms ...*PermissionRef) error {
+	_, err := c.ExecuteCommand(ctx, &DelPermCommand{PermissionScope: scope, Permissions: perms})
+	return err
+}
+
+// PermListCommand lists the permissions in a scope, like servergrouppermlist.
+type PermListCommand struct {
+	PermissionScope
+
+	// Names lists permission names instead of ids.
+	Names bool
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PermListCommand) GetResponseType() interface{} {
+	return make([]*Permission, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *PermListCommand) GetCommandName() string {
+	if c.Names {
+		return c.commandPrefix + "permlist -permsid"
+	}
+	return c.commandPrefix + "permlist"
+}
+
+// GetPermissions returns the permissions in a scope.
+// If names is set, the permissions have a Name instead of an Id.
+// Returns an empty list if no permissions are set.
+func (c *Commands) GetPermissions(ctx context.Context, scope PermissionScope, names bool) ([]*Permission, error) {
+	i, err := c.ExecuteCommand(ctx, &PermListCommand{PermissionScope: scope, Names: names})
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) || errors.Is(err, ErrPermissionEmptyResult) {
+			return make([]*Permission, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*Permission), nil
+}
+
+// PermissionCache maps permission names to ids and back.
+// The permission list is loaded on first use.
+type PermissionCache struct {
+	// api is the api to query.
+	api *ServerQueryAPI
+
+	// mtx guards the fields below.
+	mtx sync.Mutex
+	// loaded is set once the permission list has been loaded.
+	loaded bool
+	// ids maps names to ids.
+	ids map[string]int
+	// names maps ids to names.
+	names map[int]string
+}
+
+// NewPermissionCache builds a new permission cache.
+func NewPermissionCache(api *ServerQueryAPI) *PermissionCache {
+	return &PermissionCache{
+		api:   api,
+		ids:   make(map[string]int),
+		names: make(map[int]string),
+	}
+}
+
+// load loads the permission list if it is not loaded.
+// The caller must hold mtx.
+func (p *PermissionCache) load(ctx context.Context) error {
+	if p.loaded {
+		return nil
+	}
+	perms, err := p.api.GetPermissionList(ctx)
+	if err != nil {
+		return err
+	}
+	for _, perm := range perms {
+		p.ids[perm.Name] = perm.Id
+		p.names[perm.Id] = perm.Name
+	}
+	p.loaded = true
+	return nil
+}
+
+// ID returns the id of a permission name.
+func (p *PermissionCache) ID(ctx context.Context, name string) (int, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if id, ok := p.ids[name]; ok {
+		return id, nil
+	}
+	if err := p.load(ctx); err != nil {
+		return 0, err
+	}
+	if id, ok := p.ids[name]; ok {
+		return id, nil
+	}
+
+	// the permission list may not be visible to the query client
+	refs, err := p.api.GetPermissionIdsByName(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	for _, ref := range refs {
+		p.ids[ref.Name] = ref.Id
+		p.names[ref.Id] = ref.Name
+	}
+	if id, ok := p.ids[name]; ok {
+		return id, nil
+	}
+	return 0, errors.Wrapf(ErrInvalidPermissionID, "unknown permission %q", name)
+}
+
+// Name returns the name of a permission id.
+func (p *PermissionCache) Name(ctx context.Context, id int) (string, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if err := p.load(ctx); err != nil {
+		return "", err
+	}
+	if name, ok := p.names[id]; ok {
+		return name, nil
+	}
+	return "", errors.Wrapf(ErrInvalidPermissionID, "unknown permission id %d", id)
+}
+
+// Resolve fills in the missing id or name of a permission reference.
+func (p *PermissionCache) Resolve(ctx context.Context, ref *PermissionRef) error {
+	var err error
+	if ref.Name == "" {
+		ref.Name, err = p.Name(ctx, ref.Id)
+	} else if ref.Id == 0 {
+		ref.Id, err = p.ID(ctx, ref.Name)
+	}
+	return err
+}
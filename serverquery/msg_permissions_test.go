@@ -0,0 +1,121 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// TestMarshalPermissionCommands tests the wire strings of the permission commands in each scope.
+func TestMarshalPermissionCommands(t *testing.T) {
+	perms := []*Permission{
+		{PermissionRef: PermissionRef{Name: "i_client_talk_power"}, Value: 50, Skip: true},
+		{PermissionRef: PermissionRef{Id: 12}, Value: 1, Negated: true},
+	}
+	refs := []*PermissionRef{{Name: "b_client_skip_channelgroup_permissions"}, {Id: 12}}
+	cases := []struct {
+		cmd      Command
+		expected string
+	}{
+		{
+			&AddPermCommand{PermissionScope: ServerGroupPermissions(6), Permissions: perms},
+			"servergroupaddperm sgid=6 permsid=i_client_talk_power permvalue=50 permskip=1|permid=12 permvalue=1 permnegated=1",
+		},
+		{
+			&AddPermCommand{PermissionScope: ChannelGroupPermissions(5), Permissions: perms[1:]},
+			"channelgroupaddperm cgid=5 permid=12 permvalue=1 permnegated=1",
+		},
+		{
+			&AddPermCommand{PermissionScope: ChannelPermissions(3), Permissions: perms[:1]},
+			"channeladdperm cid=3 permsid=i_client_talk_power permvalue=50 permskip=1",
+		},
+		{
+			&DelPermCommand{PermissionScope: ClientPermissions(12), Permissions: refs},
+			"clientdelperm cldbid=12 permsid=b_client_skip_channelgroup_permissions|permid=12",
+		},
+		{
+			&DelPermCommand{PermissionScope: ChannelClientPermissions(3, 12), Permissions: refs[1:]},
+			"channelclientdelperm cid=3 cldbid=12 permid=12",
+		},
+		{
+			&PermListCommand{PermissionScope: ChannelClientPermissions(3, 12), Names: true},
+			"channelclientpermlist -permsid cid=3 cldbid=12",
+		},
+		{&PermIdGetByNameCommand{Names: []string{"a", "b"}}, "permidgetbyname permsid=a|permsid=b"},
+		{&PermOverviewCommand{ChannelId: 3, ClientDBId: 12}, "permoverview cid=3 cldbid=12 permid=0"},
+		{&PermFindCommand{PermissionRef: PermissionRef{Name: "i_client_talk_power"}}, "permfind permsid=i_client_talk_power"},
+	}
+	for _, c := range cases {
+		str, err := MarshalCommand(c.cmd)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if str != c.expected {
+			t.Fatalf("expected %s, got: %s", c.expected, str)
+		}
+	}
+}
+
+// TestPermissionCache tests resolving permission names and ids.
+func TestPermissionCache(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"permissionlist": {
+			"group_id_end=0 group_amount=0|permid=1 permname=b_serverinstance_help_view permdesc=Retrieve\\sinformation|permid=2 permname=i_client_talk_power permdesc=Talk\\spower",
+			"error id=0 msg=ok",
+		},
+		"permidgetbyname permsid=i_hidden": {
+			"permsid=i_hidden permid=99",
+			"error id=0 msg=ok",
+		},
+		"permidgetbyname permsid=i_bogus": {"error id=2562 msg=invalid\\spermission\\sID"},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	cache := NewPermissionCache(api)
+	if id, err := cache.ID(ctx, "i_client_talk_power"); err != nil || id != 2 {
+		t.Fatalf("expected id 2, got %d %v", id, err)
+	}
+	if name, err := cache.Name(ctx, 1); err != nil || name != "b_serverinstance_help_view" {
+		t.Fatalf("expected b_serverinstance_help_view, got %q %v", name, err)
+	}
+	s.expectReceived(t, "permissionlist")
+
+	ref := &PermissionRef{Name: "i_hidden"}
+	if err := cache.Resolve(ctx, ref); err != nil || ref.Id != 99 {
+		t.Fatalf("expected id 99, got %d %v", ref.Id, err)
+	}
+	if _, err := cache.ID(ctx, "i_bogus"); !errors.Is(err, ErrInvalidPermissionID) {
+		t.Fatalf("expected invalid permission id error, got %v", err)
+	}
+	s.expectReceived(t, "permidgetbyname permsid=i_hidden", "permidgetbyname permsid=i_bogus")
+}
+
+// TestPermissionOverview tests decoding the permissions affecting a client.
+func TestPermissionOverview(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"permoverview cid=3 cldbid=12 permid=0": {
+			"t=0 id1=5 id2=0 p=12 v=75 n=0 s=0|t=1 id1=12 id2=0 p=12 v=50 n=1 s=0|t=4 id1=3 id2=12 p=12 v=20 n=0 s=1",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	entries, err := api.GetPermissionOverview(ctx, 3, 12, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(entries) != 3 ||
+		entries[0].Id1 != 5 || entries[0].Value != 75 ||
+		entries[1].Id1 != 12 || !entries[1].Negated ||
+		entries[2].Id1 != 3 || entries[2].Id2 != 12 || !entries[2].Skip {
+		t.Fatalf("permission overview parsed incorrectly: %#v", entries)
+	}
+	s.expectReceived(t, "permoverview cid=3 cldbid=12 permid=0")
+}
@@ -0,0 +1,158 @@
+package serverquery
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Privilege key types.
+const (
+	// TokenTypeServerGroup is a key granting a server group.
+	TokenTypeServerGroup = 0
+	// TokenTypeChannelGroup is a key granting a channel group in a channel.
+	TokenTypeChannelGroup = 1
+)
+
+// PrivilegeKey is a privilege key (token).
+// Use Subscribe[*TokenUsed] to follow up when a key is used.
+type PrivilegeKey struct {
+	// Token is the privilege key.
+	Token string `serverquery:"token"`
+	// Type is TokenTypeServerGroup or TokenTypeChannelGroup.
+	Type int `serverquery:"token_type"`
+	// GroupId is the id of the group granted by the key.
+	GroupId int `serverquery:"token_id1"`
+	// ChannelId is the id of the channel for channel group keys.
+	ChannelId int `serverquery:"token_id2"`
+	// Created is the unix timestamp the key was created.
+	Created int `serverquery:"token_created"`
+	// Description is the description of the key.
+	Description string `serverquery:"token_description"`
+	// CustomSet is the custom client properties set when the key is used.
+	CustomSet string `serverquery:"token_customset"`
+}
+
+// CreatedAt returns the time the key was created.
+func (k *PrivilegeKey) CreatedAt() time.Time {
+	return time.Unix(int64(k.Created), 0)
+}
+
+// PrivilegeKeyListCommand lists the privilege keys.
+type PrivilegeKeyListCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *PrivilegeKeyListCommand) GetResponseType() interface{} {
+	return make([]*PrivilegeKey, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *PrivilegeKeyListCommand) GetCommandName() string {
+	return "privilegekeylist"
+}
+
+// GetPrivilegeKeyList returns the list of privilege keys.
+// Returns an empty list if there are no keys.
+func (c *Commands) GetPrivilegeKeyList(ctx context.Context) ([]*PrivilegeKey, error) {
+	i, err := c.ExecuteCommand(ctx, &PrivilegeKeyListCommand{})
+	if err != nil {
+		if errors.Is(err, ErrDatabaseEmptyResult) {
+			return make([]*PrivilegeKey, 0), nil
+		}
+		return nil, err
+	}
+	return i.([]*PrivilegeKey), nil
+}
+
+// PrivilegeKeyAddCommand creates a privilege key.
+type PrivilegeKeyAddCommand struct {
+	// Type is TokenTypeServerGroup or TokenTypeChannelGroup.
+	Type int `serverquery:"tokentype"`
+	// GroupId is the id of the group granted by the key.
+	GroupId int `serverquery:"tokenid1"`
+	// ChannelId is the id of the channel for channel group keys, 0 otherwise.
+	ChannelId int `serverquery:"tokenid2"`
+	// Description is the description of the key.
+	Description string `serverquery:"tokendescription,omitempty"`
+	// CustomSet is the custom client properties set when the key is used,
+	// like ident=forum_user\svalue=alice.
+	CustomSet string `serverquery:"tokencustomset,omitempty"`
+}
+
+// PrivilegeKeyAddResponse is the response to privilegekeyadd.
+type PrivilegeKeyAddResponse struct {
+	// Token is the new privilege key.
+	Token string `serverquery:"token"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PrivilegeKeyAddCommand) GetResponseType() interface{} {
+	return &PrivilegeKeyAddResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *PrivilegeKeyAddCommand) GetCommandName() string {
+	return "privilegekeyadd"
+}
+
+// AddPrivilegeKey creates a privilege key, returning the key.
+// Created is not set, use GetPrivilegeKeyList to read it.
+func (c *Commands) AddPrivilegeKey(ctx context.Context, cmd *PrivilegeKeyAddCommand) (*PrivilegeKey, error) {
+	i, err := c.ExecuteCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivilegeKey{
+		Token:       i.(*PrivilegeKeyAddResponse).Token,
+		Type:        cmd.Type,
+		GroupId:     cmd.GroupId,
+		ChannelId:   cmd.ChannelId,
+		Description: cmd.Description,
+		CustomSet:   cmd.CustomSet,
+	}, nil
+}
+
+// PrivilegeKeyDeleteCommand deletes a privilege key.
+type PrivilegeKeyDeleteCommand struct {
+	// Token is the privilege key.
+	Token string `serverquery:"token"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PrivilegeKeyDeleteCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *PrivilegeKeyDeleteCommand) GetCommandName() string {
+	return "privilegekeydelete"
+}
+
+// DeletePrivilegeKey deletes a privilege key.
+func (c *Commands) DeletePrivilegeKey(ctx context.Context, token string) error {
+	_, err := c.ExecuteCommand(ctx, &PrivilegeKeyDeleteCommand{Token: token})
+	return err
+}
+
+// PrivilegeKeyUseCommand uses a privilege key with the query client.
+type PrivilegeKeyUseCommand struct {
+	// Token is the privilege key.
+	Token string `serverquery:"token"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *PrivilegeKeyUseCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *PrivilegeKeyUseCommand) GetCommandName() string {
+	return "privilegekeyuse"
+}
+
+// UsePrivilegeKey uses a privilege key with the query client.
+func (c *Commands) UsePrivilegeKey(ctx context.Context, token string) error {
+	_, err := c.ExecuteCommand(ctx, &PrivilegeKeyUseCommand{Token: token})
+	return err
+}
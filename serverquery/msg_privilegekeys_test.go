@@ -0,0 +1,57 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestPrivilegeKeys tests creating and listing privilege keys and the tokenused event.
+func TestPrivilegeKeys(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"privilegekeyadd tokentype=0 tokenid1=6 tokenid2=0 tokendescription=New\\smember": {
+			"token=eKnFZQ9EK7G7MhtuQB6+N2B1PNZZ6OZL3ycDp2OW",
+			"error id=0 msg=ok",
+		},
+		"privilegekeylist": {
+			"token=eKnFZQ9EK7G7MhtuQB6+N2B1PNZZ6OZL3ycDp2OW token_type=0 token_id1=6 token_id2=0 token_created=1600000000 token_description=New\\smember",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	used := Subscribe[*TokenUsed](api)
+
+	key, err := api.AddPrivilegeKey(ctx, &PrivilegeKeyAddCommand{
+		Type:        TokenTypeServerGroup,
+		GroupId:     6,
+		Description: "New member",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if key.Token != "eKnFZQ9EK7G7MhtuQB6+N2B1PNZZ6OZL3ycDp2OW" || key.GroupId != 6 {
+		t.Fatalf("key returned incorrectly: %#v", key)
+	}
+
+	keys, err := api.GetPrivilegeKeyList(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(keys) != 1 || keys[0].Token != key.Token || keys[0].Description != "New member" || keys[0].CreatedAt().Unix() != 1600000000 {
+		t.Fatalf("key list parsed incorrectly: %#v", keys)
+	}
+
+	s.writeLine("notifytokenused clid=7 cldbid=12 cluid=alice= token=" + key.Token + " tokencustomset token1=6 token2=0")
+	select {
+	case eve := <-used.Events():
+		if eve.Token != key.Token || eve.ClientDatabaseId != 12 || eve.GroupId != 6 {
+			t.Fatalf("token used event parsed incorrectly: %#v", eve)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for token used event")
+	}
+}
@@ -2,9 +2,45 @@ package serverquery
 
 import (
 	"context"
+
 	"github.com/pkg/errors"
 )
 
+// ServerBasicInfo contains the commonly edited properties of a virtual server.
+type ServerBasicInfo struct {
+	// Name is the name of the server.
+	Name string `serverquery:"virtualserver_name"`
+	// PhoneticName is the phonetic name of the server.
+	PhoneticName string `serverquery:"virtualserver_name_phonetic"`
+	// CodecEncryptionMode is the codec encryption mode.
+	// 0 = configured per channel, 1 = globally off, 2 = globally on
+	CodecEncryptionMode int `serverquery:"virtualserver_codec_encryption_mode"`
+	// DefaultServerGroup is the server group assigned to new clients.
+	DefaultServerGroup int `serverquery:"virtualserver_default_server_group"`
+	// DefaultChannelGroup is the channel group assigned to new clients.
+	DefaultChannelGroup int `serverquery:"virtualserver_default_channel_group"`
+	// HostBannerURL is the URL the host banner links to.
+	HostBannerURL string `serverquery:"virtualserver_hostbanner_url"`
+	// HostBannerGfxURL is the URL of the host banner image.
+	HostBannerGfxURL string `serverquery:"virtualserver_hostbanner_gfx_url"`
+	// HostBannerGfxInterval is the host banner reload interval in seconds.
+	HostBannerGfxInterval int `serverquery:"virtualserver_hostbanner_gfx_interval"`
+	// HostBannerMode is how the host banner is scaled.
+	HostBannerMode int `serverquery:"virtualserver_hostbanner_mode"`
+	// HostButtonTooltip is the tooltip of the host button.
+	HostButtonTooltip string `serverquery:"virtualserver_hostbutton_tooltip"`
+	// HostButtonURL is the URL the host button links to.
+	HostButtonURL string `serverquery:"virtualserver_hostbutton_url"`
+	// HostButtonGfxURL is the URL of the host button image.
+	HostButtonGfxURL string `serverquery:"virtualserver_hostbutton_gfx_url"`
+	// PrioritySpeakerDimmModificator is the volume reduction while a priority speaker talks.
+	PrioritySpeakerDimmModificator float64 `serverquery:"virtualserver_priority_speaker_dimm_modificator"`
+	// IconId is the id of the server icon.
+	IconId int `serverquery:"virtualserver_icon_id"`
+	// ChannelTempDeleteDelayDefault is the default delete delay of temporary channels.
+	ChannelTempDeleteDelayDefault int `serverquery:"virtualserver_channel_temp_delete_delay_default"`
+}
+
 // UseCommand is the use command.
 type UseCommand struct {
 	// Port is the server to use.
@@ -23,8 +59,36 @@ func (c *UseCommand) GetCommandName() string {
 
 // UseServer selects a server by port
 func (c *ServerQueryAPI) UseServer(ctx context.Context, port int) error {
-	_, err := c.ExecuteCommand(ctx, &UseCommand{Port: port})
-	return err
+	if _, err := c.ExecuteCommand(ctx, &UseCommand{Port: port}); err != nil {
+		return err
+	}
+	c.session.setServerPort(port)
+	return nil
+}
+
+// UseServerIDCommand is the use command selecting a server by id.
+type UseServerIDCommand struct {
+	// ServerID is the id of the server to use.
+	ServerID int `serverquery:"sid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *UseServerIDCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *UseServerIDCommand) GetCommandName() string {
+	return "use"
+}
+
+// UseServerByID selects a server by id
+func (c *ServerQueryAPI) UseServerByID(ctx context.Context, sid int) error {
+	if _, err := c.ExecuteCommand(ctx, &UseServerIDCommand{ServerID: sid}); err != nil {
+		return err
+	}
+	c.session.setServerID(sid)
+	return nil
 }
 
 // LoginCommand is the login command.
@@ -49,6 +113,353 @@ func (c *ServerQueryAPI) Login(ctx context.Context, username, password string) e
 		return errors.New("username and password must not be nil")
 	}
 
-	_, err := c.ExecuteCommand(ctx, &LoginCommand{username: username, password: password})
+	if _, err := c.ExecuteCommand(ctx, &LoginCommand{username: username, password: password}); err != nil {
+		return err
+	}
+	c.session.setLogin(username, password)
+	return nil
+}
+
+// ServerInfo contains information about a virtual server.
+type ServerInfo struct {
+	ServerBasicInfo
+
+	// Id is the id of the virtual server.
+	Id int `serverquery:"virtualserver_id"`
+	// Port is the voice port of the virtual server.
+	Port int `serverquery:"virtualserver_port"`
+	// UniqueIdentifier is the unique identifier of the virtual server.
+	UniqueIdentifier string `serverquery:"virtualserver_unique_identifier"`
+	// Status is the status of the virtual server, like online.
+	Status string `serverquery:"virtualserver_status"`
+	// MaxClients is the number of client slots.
+	MaxClients int `serverquery:"virtualserver_maxclients"`
+	// ReservedSlots is the number of slots reserved for clients with the reserved slot permission.
+	ReservedSlots int `serverquery:"virtualserver_reserved_slots"`
+	// ClientsOnline is the number of clients online, including query clients.
+	ClientsOnline int `serverquery:"virtualserver_clientsonline"`
+	// QueryClientsOnline is the number of query clients online.
+	QueryClientsOnline int `serverquery:"virtualserver_queryclientsonline"`
+	// ChannelsOnline is the number of channels.
+	ChannelsOnline int `serverquery:"virtualserver_channelsonline"`
+	// Uptime is the uptime in seconds.
+	Uptime int `serverquery:"virtualserver_uptime"`
+	// Created is the unix timestamp the server was created.
+	Created int `serverquery:"virtualserver_created"`
+	// Autostart is set if the server starts with the instance.
+	Autostart bool `serverquery:"virtualserver_autostart"`
+	// MachineId is the machine id of the server.
+	MachineId string `serverquery:"virtualserver_machine_id"`
+	// Platform is the platform the server runs on.
+	Platform string `serverquery:"virtualserver_platform"`
+	// Version is the server version.
+	Version string `serverquery:"virtualserver_version"`
+	// WelcomeMessage is the message shown to clients on connect.
+	WelcomeMessage string `serverquery:"virtualserver_welcomemessage"`
+	// HasPassword is set if the server requires a password.
+	HasPassword bool `serverquery:"virtualserver_flag_password"`
+	// HostMessage is the host message.
+	HostMessage string `serverquery:"virtualserver_hostmessage"`
+	// HostMessageMode is how the host message is shown.
+	// 0 = none, 1 = log, 2 = modal, 3 = modal and quit
+	HostMessageMode int `serverquery:"virtualserver_hostmessage_mode"`
+	// DefaultChannelAdminGroup is the channel group assigned to channel creators.
+	DefaultChannelAdminGroup int `serverquery:"virtualserver_default_channel_admin_group"`
+	// NeededIdentitySecurityLevel is the identity security level needed to connect.
+	NeededIdentitySecurityLevel int `serverquery:"virtualserver_needed_identity_security_level"`
+	// MinClientsInChannelBeforeForcedSilence is the number of clients in a channel before it is silenced.
+	MinClientsInChannelBeforeForcedSilence int `serverquery:"virtualserver_min_clients_in_channel_before_forced_silence"`
+	// ClientConnections is the total number of client connections.
+	ClientConnections int `serverquery:"virtualserver_client_connections"`
+	// QueryClientConnections is the total number of query client connections.
+	QueryClientConnections int `serverquery:"virtualserver_query_client_connections"`
+	// MonthBytesDownloaded is the number of bytes downloaded this month.
+	MonthBytesDownloaded int `serverquery:"virtualserver_month_bytes_downloaded"`
+	// MonthBytesUploaded is the number of bytes uploaded this month.
+	MonthBytesUploaded int `serverquery:"virtualserver_month_bytes_uploaded"`
+	// TotalBytesDownloaded is the number of bytes downloaded total.
+	TotalBytesDownloaded int `serverquery:"virtualserver_total_bytes_downloaded"`
+	// TotalBytesUploaded is the number of bytes uploaded total.
+	TotalBytesUploaded int `serverquery:"virtualserver_total_bytes_uploaded"`
+	// WeblistEnabled is set if the server is announced to the server list.
+	WeblistEnabled bool `serverquery:"virtualserver_weblist_enabled"`
+	// AskForPrivilegeKey is set while the initial privilege key has not been used.
+	AskForPrivilegeKey bool `serverquery:"virtualserver_ask_for_privilegekey"`
+	// IP is the list of IPs the server binds to.
+	IP string `serverquery:"virtualserver_ip"`
+	// FileBase is the file base path of the server.
+	FileBase string `serverquery:"virtualserver_filebase"`
+}
+
+// GetServerInfoCommand requests information about the selected virtual server.
+type GetServerInfoCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *GetServerInfoCommand) GetResponseType() interface{} {
+	return &ServerInfo{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *GetServerInfoCommand) GetCommandName() string {
+	return "serverinfo"
+}
+
+// GetServerInfo returns information about the selected virtual server.
+func (c *Commands) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	i, err := c.ExecuteCommand(ctx, &GetServerInfoCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*ServerInfo), nil
+}
+
+// ServerListEntry is an entry in the virtual server list.
+type ServerListEntry struct {
+	// Id is the id of the virtual server.
+	Id int `serverquery:"virtualserver_id"`
+	// Port is the voice port of the virtual server.
+	Port int `serverquery:"virtualserver_port"`
+	// Status is the status of the virtual server, like online.
+	Status string `serverquery:"virtualserver_status"`
+	// ClientsOnline is the number of clients online, including query clients.
+	ClientsOnline int `serverquery:"virtualserver_clientsonline"`
+	// QueryClientsOnline is the number of query clients online.
+	QueryClientsOnline int `serverquery:"virtualserver_queryclientsonline"`
+	// MaxClients is the number of client slots.
+	MaxClients int `serverquery:"virtualserver_maxclients"`
+	// Uptime is the uptime in seconds.
+	Uptime int `serverquery:"virtualserver_uptime"`
+	// Name is the name of the server.
+	Name string `serverquery:"virtualserver_name"`
+	// Autostart is set if the server starts with the instance.
+	Autostart bool `serverquery:"virtualserver_autostart"`
+	// MachineId is the machine id of the server, listed with -all.
+	MachineId string `serverquery:"virtualserver_machine_id"`
+	// UniqueIdentifier is the unique identifier of the server, listed with -uid.
+	UniqueIdentifier string `serverquery:"virtualserver_unique_identifier"`
+}
+
+// GetServerListCommand lists the virtual servers.
+type GetServerListCommand struct{}
+
+// GetResponseType returns an instance of the response type.
+func (c *GetServerListCommand) GetResponseType() interface{} {
+	return make([]*ServerListEntry, 0)
+}
+
+// GetCommandName returns the name of the command.
+func (c *GetServerListCommand) GetCommandName() string {
+	return "serverlist -uid -all"
+}
+
+// GetServerList returns the virtual servers of all machines.
+func (c *Commands) GetServerList(ctx context.Context) ([]*ServerListEntry, error) {
+	i, err := c.ExecuteCommand(ctx, &GetServerListCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return i.([]*ServerListEntry), nil
+}
+
+// ServerIdGetByPortCommand looks up a virtual server id by voice port.
+type ServerIdGetByPortCommand struct {
+	// Port is the voice port of the virtual server.
+	Port int `serverquery:"virtualserver_port"`
+}
+
+// ServerIdGetByPortResponse is the response to serveridgetbyport.
+type ServerIdGetByPortResponse struct {
+	// ServerID is the id of the virtual server.
+	ServerID int `serverquery:"server_id"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerIdGetByPortCommand) GetResponseType() interface{} {
+	return &ServerIdGetByPortResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerIdGetByPortCommand) GetCommandName() string {
+	return "serveridgetbyport"
+}
+
+// GetServerIdByPort returns the id of the virtual server with a voice port.
+func (c *Commands) GetServerIdByPort(ctx context.Context, port int) (int, error) {
+	i, err := c.ExecuteCommand(ctx, &ServerIdGetByPortCommand{Port: port})
+	if err != nil {
+		return 0, err
+	}
+	return i.(*ServerIdGetByPortResponse).ServerID, nil
+}
+
+// ServerProperties are the properties that can be set with servercreate and serveredit.
+// Nil fields are not sent.
+type ServerProperties struct {
+	// Name is the name of the server.
+	Name *string `serverquery:"virtualserver_name,omitempty"`
+	// PhoneticName is the phonetic name of the server.
+	PhoneticName *string `serverquery:"virtualserver_name_phonetic,omitempty"`
+	// Port is the voice port of the server.
+	Port *int `serverquery:"virtualserver_port,omitempty"`
+	// MaxClients is the number of client slots.
+	MaxClients *int `serverquery:"virtualserver_maxclients,omitempty"`
+	// ReservedSlots is the number of slots reserved for clients with the reserved slot permission.
+	ReservedSlots *int `serverquery:"virtualserver_reserved_slots,omitempty"`
+	// Password is the server password.
+	Password *string `serverquery:"virtualserver_password,omitempty"`
+	// WelcomeMessage is the message shown to clients on connect.
+	WelcomeMessage *string `serverquery:"virtualserver_welcomemessage,omitempty"`
+	// HostMessage is the host message.
+	HostMessage *string `serverquery:"virtualserver_hostmessage,omitempty"`
+	// HostMessageMode is how the host message is shown.
+	HostMessageMode *int `serverquery:"virtualserver_hostmessage_mode,omitempty"`
+	// Autostart is set if the server starts with the instance.
+	Autostart *bool `serverquery:"virtualserver_autostart,omitempty"`
+	// DefaultServerGroup is the server group assigned to new clients.
+	DefaultServerGroup *int `serverquery:"virtualserver_default_server_group,omitempty"`
+	// DefaultChannelGroup is the channel group assigned to new clients.
+	DefaultChannelGroup *int `serverquery:"virtualserver_default_channel_group,omitempty"`
+	// DefaultChannelAdminGroup is the channel group assigned to channel creators.
+	DefaultChannelAdminGroup *int `serverquery:"virtualserver_default_channel_admin_group,omitempty"`
+	// CodecEncryptionMode is the codec encryption mode.
+	CodecEncryptionMode *int `serverquery:"virtualserver_codec_encryption_mode,omitempty"`
+	// HostBannerURL is the URL the host banner links to.
+	HostBannerURL *string `serverquery:"virtualserver_hostbanner_url,omitempty"`
+	// HostBannerGfxURL is the URL of the host banner image.
+	HostBannerGfxURL *string `serverquery:"virtualserver_hostbanner_gfx_url,omitempty"`
+	// HostButtonTooltip is the tooltip of the host button.
+	HostButtonTooltip *string `serverquery:"virtualserver_hostbutton_tooltip,omitempty"`
+	// HostButtonURL is the URL the host button links to.
+	HostButtonURL *string `serverquery:"virtualserver_hostbutton_url,omitempty"`
+	// IconId is the id of the server icon.
+	IconId *int `serverquery:"virtualserver_icon_id,omitempty"`
+	// NeededIdentitySecurityLevel is the identity security level needed to connect.
+	NeededIdentitySecurityLevel *int `serverquery:"virtualserver_needed_identity_security_level,omitempty"`
+	// WeblistEnabled is set if the server is announced to the server list.
+	WeblistEnabled *bool `serverquery:"virtualserver_weblist_enabled,omitempty"`
+}
+
+// ServerCreateCommand creates a virtual server.
+type ServerCreateCommand struct {
+	ServerProperties
+}
+
+// ServerCreateResponse is the response to servercreate.
+type ServerCreateResponse struct {
+	// ServerID is the id of the new virtual server.
+	ServerID int `serverquery:"sid"`
+	// Port is the voice port of the new virtual server.
+	Port int `serverquery:"virtualserver_port"`
+	// Token is the privilege key granting the server admin group.
+	Token string `serverquery:"token"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerCreateCommand) GetResponseType() interface{} {
+	return &ServerCreateResponse{}
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerCreateCommand) GetCommandName() string {
+	return "servercreate"
+}
+
+// CreateServer creates and starts a virtual server.
+// The name overrides props.Name.
+func (c *Commands) CreateServer(ctx context.Context, name string, props ServerProperties) (*ServerCreateResponse, error) {
+	props.Name = &name
+	i, err := c.ExecuteCommand(ctx, &ServerCreateCommand{ServerProperties: props})
+	if err != nil {
+		return nil, err
+	}
+	return i.(*ServerCreateResponse), nil
+}
+
+// ServerEditCommand changes the properties of the selected virtual server.
+type ServerEditCommand struct {
+	ServerProperties
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerEditCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerEditCommand) GetCommandName() string {
+	return "serveredit"
+}
+
+// EditServer changes the properties of the selected virtual server.
+func (c *Commands) EditServer(ctx context.Context, props ServerProperties) error {
+	_, err := c.ExecuteCommand(ctx, &ServerEditCommand{ServerProperties: props})
+	return err
+}
+
+// ServerDeleteCommand deletes a stopped virtual server.
+type ServerDeleteCommand struct {
+	// ServerID is the id of the virtual server.
+	ServerID int `serverquery:"sid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerDeleteCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerDeleteCommand) GetCommandName() string {
+	return "serverdelete"
+}
+
+// DeleteServer deletes a stopped virtual server.
+func (c *Commands) DeleteServer(ctx context.Context, sid int) error {
+	_, err := c.ExecuteCommand(ctx, &ServerDeleteCommand{ServerID: sid})
+	return err
+}
+
+// ServerStartCommand starts a virtual server.
+type ServerStartCommand struct {
+	// ServerID is the id of the virtual server.
+	ServerID int `serverquery:"sid"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerStartCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerStartCommand) GetCommandName() string {
+	return "serverstart"
+}
+
+// StartServer starts a virtual server.
+func (c *Commands) StartServer(ctx context.Context, sid int) error {
+	_, err := c.ExecuteCommand(ctx, &ServerStartCommand{ServerID: sid})
+	return err
+}
+
+// ServerStopCommand stops a virtual server.
+type ServerStopCommand struct {
+	// ServerID is the id of the virtual server.
+	ServerID int `serverquery:"sid"`
+	// ReasonMessage is the message shown to the clients.
+	ReasonMessage string `serverquery:"reasonmsg,omitempty"`
+}
+
+// GetResponseType returns an instance of the response type.
+func (c *ServerStopCommand) GetResponseType() interface{} {
+	return nil
+}
+
+// GetCommandName returns the name of the command.
+func (c *ServerStopCommand) GetCommandName() string {
+	return "serverstop"
+}
+
+// StopServer stops a virtual server.
+func (c *Commands) StopServer(ctx context.Context, sid int, reasonMessage string) error {
+	_, err := c.ExecuteCommand(ctx, &ServerStopCommand{ServerID: sid, ReasonMessage: reasonMessage})
 	return err
 }
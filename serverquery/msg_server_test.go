@@ -0,0 +1,57 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+)
+
+// TestVirtualServerAdmin tests listing and creating virtual servers.
+func TestVirtualServerAdmin(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"serverlist -uid -all": {
+			"virtualserver_id=1 virtualserver_port=9987 virtualserver_status=online virtualserver_clientsonline=3 virtualserver_queryclientsonline=1 virtualserver_maxclients=32 virtualserver_uptime=3600 virtualserver_name=Main virtualserver_autostart=1 virtualserver_machine_id virtualserver_unique_identifier=abc=|" +
+				"virtualserver_id=2 virtualserver_port=9988 virtualserver_status=offline virtualserver_maxclients=10 virtualserver_name=Test virtualserver_autostart=0 virtualserver_machine_id virtualserver_unique_identifier=def=",
+			"error id=0 msg=ok",
+		},
+		"servercreate virtualserver_name=Events virtualserver_maxclients=64": {
+			"sid=3 token=Adm1nT0ken virtualserver_port=9989",
+			"error id=0 msg=ok",
+		},
+		"use sid=3": {"error id=0 msg=ok"},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	servers, err := api.GetServerList(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(servers) != 2 || servers[0].Port != 9987 || !servers[0].Autostart || servers[1].Status != "offline" {
+		t.Fatalf("server list parsed incorrectly: %#v", servers)
+	}
+
+	maxClients := 64
+	created, err := api.CreateServer(ctx, "Events", ServerProperties{MaxClients: &maxClients})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if created.ServerID != 3 || created.Port != 9989 || created.Token != "Adm1nT0ken" {
+		t.Fatalf("servercreate parsed incorrectly: %#v", created)
+	}
+
+	if err := api.UseServerByID(ctx, created.ServerID); err != nil {
+		t.Fatal(err.Error())
+	}
+	s.expectReceived(t, "serverlist -uid -all", "servercreate virtualserver_name=Events virtualserver_maxclients=64", "use sid=3")
+
+	// the selected server is replayed after reconnecting
+	cmds := api.session.buildCommands()
+	if len(cmds) != 1 {
+		t.Fatalf("expected 1 replay command, got %d", len(cmds))
+	}
+	if mc, _ := MarshalCommand(cmds[0]); mc != "use sid=3" {
+		t.Fatalf("expected use sid=3 to be replayed, got %q", mc)
+	}
+}
@@ -0,0 +1,161 @@
+package serverquery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimitConfig configures client-side flood protection.
+type RateLimitConfig struct {
+	// Commands is the number of commands allowed per Period.
+	// This matches serverinstance_serverquery_flood_commands.
+	Commands int
+	// Period is the time window for Commands.
+	// This matches serverinstance_serverquery_flood_time.
+	Period time.Duration
+	// FloodBackoff is the delay before retrying a command rejected for flooding.
+	// The delay doubles with each retry.
+	FloodBackoff time.Duration
+	// MaxFloodRetries is the number of times to retry a command rejected for flooding.
+	MaxFloodRetries int
+}
+
+// DefaultRateLimitConfig matches the documented default server flood limits.
+var DefaultRateLimitConfig = RateLimitConfig{
+	Commands:        10,
+	Period:          3 * time.Second,
+	FloodBackoff:    3 * time.Second,
+	MaxFloodRetries: 3,
+}
+
+// rateLimiter is a token bucket limiting outgoing commands.
+type rateLimiter struct {
+	mtx sync.Mutex
+	// conf is the configuration, nil if disabled.
+	conf *RateLimitConfig
+	// tokens is the number of available tokens.
+	tokens float64
+	// last is the time tokens was last refilled.
+	last time.Time
+	// now returns the current time.
+	now func() time.Time
+}
+
+// newRateLimiter builds a new rate limiter, disabled if conf is nil.
+func newRateLimiter(conf *RateLimitConfig) *rateLimiter {
+	l := &rateLimiter{now: time.Now}
+	l.setConfig(conf)
+	return l
+}
+
+// setConfig changes the configuration and refills the bucket.
+func (l *rateLimiter) setConfig(conf *RateLimitConfig) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if conf != nil {
+		c := *conf
+		conf = &c
+		l.tokens = float64(conf.Commands)
+	}
+	l.conf = conf
+	l.last = l.now()
+}
+
+// getConfig returns a copy of the configuration, nil if disabled.
+func (l *rateLimiter) getConfig() *RateLimitConfig {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.conf == nil {
+		return nil
+	}
+	c := *l.conf
+	return &c
+}
+
+// take takes a token if one is available.
+// Returns the time to wait before trying again if not.
+func (l *rateLimiter) take() time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.conf == nil || l.conf.Commands <= 0 || l.conf.Period <= 0 {
+		return 0
+	}
+
+	now := l.now()
+	perToken := l.conf.Period / time.Duration(l.conf.Commands)
+	l.tokens += float64(now.Sub(l.last)) / float64(perToken)
+	if max := float64(l.conf.Commands); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) * float64(perToken))
+}
+
+// SetRateLimit changes the client-side flood protection.
+// Pass nil to disable it, for example when the client IP is on the server query whitelist.
+func (a *ServerQueryAPI) SetRateLimit(conf *RateLimitConfig) {
+	a.rateLimiter.setConfig(conf)
+}
+
+// isFloodError checks if the error indicates the server rejected a command for flooding.
+func isFloodError(err error) bool {
+	return errors.Is(err, ErrClientFlooding) || errors.Is(err, ErrBanned)
+}
+
+// idle waits for the duration on behalf of a command while processing incoming events.
+// Gives up early if the Run loop ctx or the command ctx is done.
+func (a *ServerQueryAPI) idle(ctx, cmdCtx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case <-cmdCtx.Done():
+			return context.Canceled
+		case <-timer.C:
+			return nil
+		case env, ok := <-a.readQueue:
+			if !ok {
+				return errors.New("connection closed while waiting to send command")
+			}
+			a.processEvent(ctx, env)
+		}
+	}
+}
+
+// executePending submits a command, pacing it with the rate limiter and retrying on flood errors.
+func (a *ServerQueryAPI) executePending(ctx context.Context, cmd *pendingCommand) (interface{}, error) {
+	for attempt := 0; ; attempt++ {
+		for {
+			wait := a.rateLimiter.take()
+			if wait == 0 {
+				break
+			}
+			if err := a.idle(ctx, cmd.ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := a.submitCommand(ctx, cmd)
+		if err == nil || !isFloodError(err) {
+			return res, err
+		}
+
+		conf := a.rateLimiter.getConfig()
+		if conf == nil || attempt >= conf.MaxFloodRetries {
+			return res, err
+		}
+		if err := a.idle(ctx, cmd.ctx, conf.FloodBackoff<<uint(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TestRateLimiter tests the token bucket pacing.
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newRateLimiter(nil)
+	l.now = func() time.Time { return now }
+	l.setConfig(&RateLimitConfig{Commands: 10, Period: 3 * time.Second})
+
+	for i := 0; i < 10; i++ {
+		if wait := l.take(); wait != 0 {
+			t.Fatalf("command %d: expected no wait, got %v", i, wait)
+		}
+	}
+	if wait := l.take(); wait != 300*time.Millisecond {
+		t.Fatalf("expected to wait 300ms, got %v", wait)
+	}
+
+	now = now.Add(300 * time.Millisecond)
+	if wait := l.take(); wait != 0 {
+		t.Fatalf("expected token after refill, got wait %v", wait)
+	}
+
+	l.setConfig(nil)
+	for i := 0; i < 20; i++ {
+		if wait := l.take(); wait != 0 {
+			t.Fatalf("expected disabled limiter to never wait, got %v", wait)
+		}
+	}
+}
+
+// TestFloodRetry tests that commands rejected for flooding are retried.
+func TestFloodRetry(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"clientinfo clid=1": {"client_nickname=alice", "error id=0 msg=ok"},
+		"clientinfo clid=2": {"error id=524 msg=client\\sis\\sflooding"},
+	})
+	var flooded bool
+	s.handler = func(line string) ([]string, bool) {
+		if line != "clientinfo clid=1" || flooded {
+			return nil, false
+		}
+		flooded = true
+		return []string{"error id=524 msg=client\\sis\\sflooding"}, true
+	}
+	api.SetRateLimit(&RateLimitConfig{
+		Commands:        10,
+		Period:          time.Second,
+		FloodBackoff:    time.Millisecond,
+		MaxFloodRetries: 2,
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	info, err := api.GetClientInfo(ctx, 1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.Nickname != "alice" {
+		t.Fatalf("client info parsed incorrectly: %#v", info)
+	}
+	s.expectReceived(t, "clientinfo clid=1", "clientinfo clid=1")
+
+	_, err = api.GetClientInfo(ctx, 2)
+	if !errors.Is(err, ErrClientFlooding) {
+		t.Fatalf("expected flood error after retries, got: %v", err)
+	}
+	s.expectReceived(t, "clientinfo clid=2", "clientinfo clid=2", "clientinfo clid=2")
+}
+
+// TestFloodBackoffCanceled tests that a canceled command stops waiting for the flood backoff.
+func TestFloodBackoffCanceled(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"clientinfo clid=1": {"client_nickname=alice", "error id=0 msg=ok"},
+		"clientinfo clid=2": {"error id=524 msg=client\\sis\\sflooding"},
+	})
+	api.SetRateLimit(&RateLimitConfig{
+		Commands:        10,
+		Period:          time.Second,
+		FloodBackoff:    time.Minute,
+		MaxFloodRetries: 3,
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	cmdCtx, cmdCtxCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cmdCtxCancel()
+	if _, err := api.GetClientInfo(cmdCtx, 2); err == nil {
+		t.Fatal("expected the flooded command to fail")
+	}
+
+	// the next command is not stuck behind the abandoned backoff
+	infoCtx, infoCtxCancel := context.WithTimeout(ctx, time.Second)
+	defer infoCtxCancel()
+	info, err := api.GetClientInfo(infoCtx, 1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.Nickname != "alice" {
+		t.Fatalf("client info parsed incorrectly: %#v", info)
+	}
+	s.expectReceived(t, "clientinfo clid=2", "clientinfo clid=1")
+}
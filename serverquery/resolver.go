@@ -0,0 +1,210 @@
+package serverquery
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IdentifierKind is the kind of a client identifier.
+type IdentifierKind int
+
+const (
+	// IdentifierClientID is the id of a connected client (clid).
+	IdentifierClientID IdentifierKind = iota
+	// IdentifierDatabaseID is the database id of a client (cldbid).
+	IdentifierDatabaseID
+	// IdentifierUID is the unique identifier of a client (cluid).
+	IdentifierUID
+	// IdentifierNickname is the exact nickname of a client.
+	// Connected clients are preferred over the last nicknames in the database.
+	IdentifierNickname
+)
+
+// ResolvedClient is a client identity resolved from an identifier.
+type ResolvedClient struct {
+	// DatabaseId is the ID of the client in the database.
+	DatabaseId int
+	// UniqueIdentifier contains the client unique id.
+	UniqueIdentifier string
+	// Nickname is the current or last nickname of the client.
+	Nickname string
+	// ClientIds are the IDs of the connected clients, empty if offline.
+	ClientIds []int
+}
+
+// resolverKey is a resolver cache key.
+type resolverKey struct {
+	kind  IdentifierKind
+	value string
+}
+
+// resolverEntry is a resolver cache entry.
+type resolverEntry struct {
+	client  ResolvedClient
+	expires time.Time
+}
+
+// Resolver resolves any kind of client identifier to the client identity.
+// Results are cached for the TTL, which may leave ClientIds and Nickname stale.
+type Resolver struct {
+	// api is the api to query.
+	api *ServerQueryAPI
+	// ttl is the cache TTL, or 0 to disable caching.
+	ttl time.Duration
+
+	// mtx guards cache.
+	mtx sync.Mutex
+	// cache maps identifiers to resolved clients.
+	cache map[resolverKey]*resolverEntry
+}
+
+// NewResolver builds a new resolver.
+// If ttl is 0, results are not cached.
+func NewResolver(api *ServerQueryAPI, ttl time.Duration) *Resolver {
+	return &Resolver{
+		api:   api,
+		ttl:   ttl,
+		cache: make(map[resolverKey]*resolverEntry),
+	}
+}
+
+// Flush clears the cache.
+func (r *Resolver) Flush() {
+	r.mtx.Lock()
+	r.cache = make(map[resolverKey]*resolverEntry)
+	r.mtx.Unlock()
+}
+
+// Resolve resolves a client identifier of the given kind.
+// Numeric identifiers are given in decimal.
+func (r *Resolver) Resolve(ctx context.Context, kind IdentifierKind, value string) (*ResolvedClient, error) {
+	key := resolverKey{kind: kind, value: value}
+	if r.ttl != 0 {
+		r.mtx.Lock()
+		entry, ok := r.cache[key]
+		if ok && time.Now().Before(entry.expires) {
+			client := entry.client
+			r.mtx.Unlock()
+			return &client, nil
+		}
+		delete(r.cache, key)
+		r.mtx.Unlock()
+	}
+
+	client, err := r.resolve(ctx, kind, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ttl != 0 {
+		r.mtx.Lock()
+		r.cache[key] = &resolverEntry{client: *client, expires: time.Now().Add(r.ttl)}
+		r.mtx.Unlock()
+	}
+	return client, nil
+}
+
+// ResolveDatabaseID resolves a client identifier to the database id.
+func (r *Resolver) ResolveDatabaseID(ctx context.Context, kind IdentifierKind, value string) (int, error) {
+	client, err := r.Resolve(ctx, kind, value)
+	if err != nil {
+		return 0, err
+	}
+	return client.DatabaseId, nil
+}
+
+// resolve resolves an identifier without the cache.
+func (r *Resolver) resolve(ctx context.Context, kind IdentifierKind, value string) (*ResolvedClient, error) {
+	switch kind {
+	case IdentifierClientID:
+		clid, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse client id")
+		}
+		info, err := r.api.GetClientUIDFromClid(ctx, clid)
+		if err != nil {
+			return nil, err
+		}
+		return r.resolveUID(ctx, info.UniqueIdentifier)
+	case IdentifierDatabaseID:
+		cldbid, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse database id")
+		}
+		info, err := r.api.GetClientNameFromDBId(ctx, cldbid)
+		if err != nil {
+			return nil, err
+		}
+		return r.withClientIds(ctx, info)
+	case IdentifierUID:
+		return r.resolveUID(ctx, value)
+	case IdentifierNickname:
+		return r.resolveNickname(ctx, value)
+	default:
+		return nil, errors.Errorf("unknown identifier kind %d", int(kind))
+	}
+}
+
+// resolveUID resolves a unique id.
+func (r *Resolver) resolveUID(ctx context.Context, uid string) (*ResolvedClient, error) {
+	info, err := r.api.GetClientNameFromUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.withClientIds(ctx, info)
+}
+
+// withClientIds builds a resolved client, looking up the connected clients.
+func (r *Resolver) withClientIds(ctx context.Context, info *ClientNameInfo) (*ResolvedClient, error) {
+	client := &ResolvedClient{
+		DatabaseId:       info.DatabaseId,
+		UniqueIdentifier: info.UniqueIdentifier,
+		Nickname:         info.Nickname,
+		ClientIds:        make([]int, 0),
+	}
+	entries, err := r.api.GetClientIds(ctx, info.UniqueIdentifier)
+	if err != nil {
+		// the client is offline
+		if errors.Is(err, ErrDatabaseEmptyResult) || errors.Is(err, ErrInvalidClientID) {
+			return client, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		client.ClientIds = append(client.ClientIds, entry.ClientId)
+		client.Nickname = entry.Nickname
+	}
+	return client, nil
+}
+
+// resolveNickname resolves an exact nickname, preferring connected clients.
+func (r *Resolver) resolveNickname(ctx context.Context, nickname string) (*ResolvedClient, error) {
+	online, err := r.api.FindClients(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range online {
+		if entry.Nickname == nickname {
+			return r.resolve(ctx, IdentifierClientID, strconv.Itoa(entry.Id))
+		}
+	}
+
+	dbids, err := r.api.FindClientDB(ctx, nickname, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, cldbid := range dbids {
+		info, err := r.api.GetClientNameFromDBId(ctx, cldbid)
+		if err != nil {
+			return nil, err
+		}
+		if info.Nickname == nickname {
+			return r.withClientIds(ctx, info)
+		}
+	}
+	return nil, errors.Wrapf(ErrDatabaseEmptyResult, "no client with nickname %q", nickname)
+}
@@ -0,0 +1,68 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestResolver tests resolving identifiers with the cache.
+func TestResolver(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"clientfind pattern=alice": {
+			"clid=8 client_nickname=alice2|clid=7 client_nickname=alice",
+			"error id=0 msg=ok",
+		},
+		"clientgetuidfromclid clid=7": {
+			"clid=7 cluid=alice= nickname=alice",
+			"error id=0 msg=ok",
+		},
+		"clientgetnamefromuid cluid=alice=": {
+			"cluid=alice= cldbid=3 name=alice",
+			"error id=0 msg=ok",
+		},
+		"clientgetids cluid=alice=": {
+			"cluid=alice= clid=7 name=alice",
+			"error id=0 msg=ok",
+		},
+		"clientgetnamefromdbid cldbid=9": {
+			"cluid=bob= cldbid=9 name=bob",
+			"error id=0 msg=ok",
+		},
+		"clientgetids cluid=bob=": {"error id=1281 msg=database\\sempty\\sresult\\sset"},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	r := NewResolver(api, time.Minute)
+	client, err := r.Resolve(ctx, IdentifierNickname, "alice")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if client.DatabaseId != 3 || client.UniqueIdentifier != "alice=" || len(client.ClientIds) != 1 || client.ClientIds[0] != 7 {
+		t.Fatalf("alice resolved incorrectly: %#v", client)
+	}
+	s.expectReceived(
+		t,
+		"clientfind pattern=alice",
+		"clientgetuidfromclid clid=7",
+		"clientgetnamefromuid cluid=alice=",
+		"clientgetids cluid=alice=",
+	)
+
+	// served from the cache
+	if dbid, err := r.ResolveDatabaseID(ctx, IdentifierNickname, "alice"); err != nil || dbid != 3 {
+		t.Fatalf("expected cached database id 3, got %d %v", dbid, err)
+	}
+
+	client, err = r.Resolve(ctx, IdentifierDatabaseID, "9")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if client.UniqueIdentifier != "bob=" || client.Nickname != "bob" || len(client.ClientIds) != 0 {
+		t.Fatalf("bob resolved incorrectly: %#v", client)
+	}
+	s.expectReceived(t, "clientgetnamefromdbid cldbid=9", "clientgetids cluid=bob=")
+}
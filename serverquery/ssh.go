@@ -0,0 +1,152 @@
+package serverquery
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshConn adapts a ServerQuery SSH shell session to a net.Conn.
+type sshConn struct {
+	io.Reader
+	io.WriteCloser
+
+	client  *ssh.Client
+	session *ssh.Session
+}
+
+// Close closes the session and the underlying client.
+func (c *sshConn) Close() error {
+	c.WriteCloser.Close()
+	c.session.Close()
+	return c.client.Close()
+}
+
+// LocalAddr returns the local network address.
+func (c *sshConn) LocalAddr() net.Addr {
+	return c.client.LocalAddr()
+}
+
+// RemoteAddr returns the remote network address.
+func (c *sshConn) RemoteAddr() net.Addr {
+	return c.client.RemoteAddr()
+}
+
+// SetDeadline is not supported on SSH sessions.
+func (c *sshConn) SetDeadline(t time.Time) error {
+	return errors.New("deadlines are not supported over ssh")
+}
+
+// SetReadDeadline is not supported on SSH sessions.
+func (c *sshConn) SetReadDeadline(t time.Time) error {
+	return c.SetDeadline(t)
+}
+
+// SetWriteDeadline is not supported on SSH sessions.
+func (c *sshConn) SetWriteDeadline(t time.Time) error {
+	return c.SetDeadline(t)
+}
+
+// DialSSH attempts to dial the SSH API, usually on port 10022.
+// The query login is performed by SSH, so Login should not be called.
+// conf must set the query credentials as User and Auth, and a HostKeyCallback
+// to verify the server, for example ssh.FixedHostKey or knownhosts.New.
+func DialSSH(endp string, conf *ssh.ClientConfig) (*ServerQueryAPI, error) {
+	rw, err := dialSSHReadWriter(context.Background(), endp, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServerQueryAPI(rw), nil
+}
+
+// DialSSHSupervised builds a supervised client for the SSH API at endp.
+// The connection is established when Run is called.
+func DialSSHSupervised(endp string, conf *ssh.ClientConfig, reconnectConf *ReconnectConfig) *ServerQueryAPI {
+	return NewSupervisedServerQueryAPI(func(ctx context.Context) (*ServerQueryReadWriter, error) {
+		return dialSSHReadWriter(ctx, endp, conf)
+	}, reconnectConf)
+}
+
+// dialSSHReadWriter dials the SSH API, starts the query shell and waits for the server intro.
+func dialSSHReadWriter(ctx context.Context, endp string, conf *ssh.ClientConfig) (*ServerQueryReadWriter, error) {
+	if conf == nil || conf.HostKeyCallback == nil {
+		return nil, errors.New("ssh config must set a HostKeyCallback")
+	}
+
+	d := net.Dialer{Timeout: conf.Timeout}
+	netConn, err := d.DialContext(ctx, "tcp", endp)
+	if err != nil {
+		return nil, err
+	}
+
+	// the handshake, shell start and intro all block on netConn
+	stop := interruptOnDone(ctx, netConn)
+	rw, err := startSSHQuery(netConn, endp, conf)
+	if stopErr := stop(); stopErr != nil {
+		if rw != nil {
+			rw.Close()
+		}
+		return nil, stopErr
+	}
+	return rw, err
+}
+
+// startSSHQuery performs the SSH handshake on netConn, starts the query shell and waits for the server intro.
+// netConn is closed on error.
+func startSSHQuery(netConn net.Conn, endp string, conf *ssh.ClientConfig) (*ServerQueryReadWriter, error) {
+	cc, chans, reqs, err := ssh.NewClientConn(netConn, endp, conf)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(cc, chans, reqs)
+
+	conn, err := startSSHShell(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	rw := NewServerQueryReadWriter(conn)
+	if err := rw.waitForServerIntro(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return rw, nil
+}
+
+// startSSHShell opens the query shell session on a SSH client.
+func startSSHShell(client *ssh.Client) (*sshConn, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	if err := session.Shell(); err != nil {
+		session.Close()
+		return nil, errors.Wrap(err, "start query shell")
+	}
+
+	return &sshConn{
+		Reader:      stdout,
+		WriteCloser: stdin,
+		client:      client,
+		session:     session,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package serverquery
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startFakeSSHServer starts an in-process SSH server running a fake query shell.
+func startFakeSSHServer(
+	t *testing.T,
+	replies map[string][]string,
+) (addr string, hostKey ssh.PublicKey, servers <-chan *fakeServer) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	conf := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() != "serveradmin" || string(pass) != "secret" {
+				return nil, ssh.ErrNoAuth
+			}
+			return nil, nil
+		},
+	}
+	conf.AddHostKey(signer)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	serverCh := make(chan *fakeServer, 10)
+	go func() {
+		for {
+			netConn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeSSHConn(netConn, conf, replies, serverCh)
+		}
+	}()
+	return lis.Addr().String(), signer.PublicKey(), serverCh
+}
+
+// serveFakeSSHConn serves the query shell on a single SSH connection.
+func serveFakeSSHConn(
+	netConn net.Conn,
+	conf *ssh.ServerConfig,
+	replies map[string][]string,
+	serverCh chan<- *fakeServer,
+) {
+	sconn, chans, reqs, err := ssh.NewServerConn(netConn, conf)
+	if err != nil {
+		netConn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			newCh.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range chReqs {
+				req.Reply(req.Type == "shell", nil)
+				if req.Type != "shell" {
+					continue
+				}
+				s := &fakeServer{conn: ch, replies: replies, received: make(chan string, 100)}
+				s.writeLine("TS3")
+				s.writeLine("Welcome to the TeamSpeak 3 ServerQuery interface.")
+				go s.serve()
+				serverCh <- s
+			}
+		}()
+	}
+}
+
+// TestDialSSH tests running commands over the SSH transport.
+func TestDialSSH(t *testing.T) {
+	addr, hostKey, servers := startFakeSSHServer(t, map[string][]string{
+		"clientlist -uid": {
+			"clid=1 client_nickname=alice|clid=2 client_nickname=bob",
+			"error id=0 msg=ok",
+		},
+	})
+
+	api, err := DialSSH(addr, &ssh.ClientConfig{
+		User:            "serveradmin",
+		Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer api.Close()
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	clients, err := api.GetClientList(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(clients) != 2 || clients[1].Nickname != "bob" {
+		t.Fatalf("client list parsed incorrectly: %#v", clients)
+	}
+	(<-servers).expectReceived(t, "clientlist -uid")
+}
+
+// TestDialSSHHostKeyMismatch tests that an unexpected host key is rejected.
+func TestDialSSHHostKeyMismatch(t *testing.T) {
+	addr, _, _ := startFakeSSHServer(t, nil)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	otherKey, err := ssh.NewPublicKey(otherPub)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = DialSSH(addr, &ssh.ClientConfig{
+		User:            "serveradmin",
+		Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+		HostKeyCallback: ssh.FixedHostKey(otherKey),
+	})
+	if err == nil {
+		t.Fatal("expected host key mismatch to fail")
+	}
+
+	if _, err := DialSSH(addr, &ssh.ClientConfig{User: "serveradmin"}); err == nil {
+		t.Fatal("expected missing host key callback to fail")
+	}
+}
+
+// TestDialSSHCanceledDuringHandshake tests that ctx stops a handshake with a stalled server.
+func TestDialSSHCanceledDuringHandshake(t *testing.T) {
+	addr := startStalledListener(t)
+	_, hostKey, _ := startFakeSSHServer(t, nil)
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer ctxCancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := dialSSHReadWriter(ctx, addr, &ssh.ClientConfig{
+			User:            "serveradmin",
+			Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+		})
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != context.DeadlineExceeded {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("dial did not return after ctx expired")
+	}
+}
@@ -0,0 +1,379 @@
+package serverquery
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// StateChangeType is the type of a state change.
+type StateChangeType int
+
+const (
+	// StateResynced indicates the state was reloaded from the server.
+	StateResynced StateChangeType = iota
+	// StateClientJoined indicates a client joined the server.
+	StateClientJoined
+	// StateClientLeft indicates a client left the server.
+	StateClientLeft
+	// StateClientMoved indicates a client switched channels.
+	StateClientMoved
+	// StateChannelCreated indicates a channel was created.
+	StateChannelCreated
+	// StateChannelEdited indicates channel properties were edited.
+	StateChannelEdited
+	// StateChannelDeleted indicates a channel was deleted.
+	StateChannelDeleted
+	// StateChannelMoved indicates a channel was moved.
+	StateChannelMoved
+	// StateServerEdited indicates the server properties were edited.
+	StateServerEdited
+)
+
+// StateChange describes a change to the state mirror.
+type StateChange struct {
+	// Type is the type of the change.
+	Type StateChangeType
+	// ClientId is the client the change applies to, if any.
+	ClientId int
+	// ChannelId is the channel the change applies to.
+	// For client changes this is the channel the client is in, or was in when leaving.
+	ChannelId int
+	// PreviousChannelId is the previous channel of a moved client,
+	// or the previous parent channel of a moved channel.
+	PreviousChannelId int
+	// Error is set if a resync failed.
+	Error error
+}
+
+// State mirrors the clients and channels of a virtual server, kept in sync from events.
+// The client must be registered for the "server" and "channel" (id 0) notifications.
+// All methods are thread-safe.
+type State struct {
+	// api is the api the state is mirrored from.
+	api *ServerQueryAPI
+	// changes contains the state changes.
+	changes chan *StateChange
+	// droppedChanges is the number of changes dropped due to a full buffer.
+	droppedChanges uint64
+
+	// mtx guards the fields below.
+	mtx sync.RWMutex
+	// server is the virtual server info.
+	server ServerBasicInfo
+	// clients maps client ids to clients.
+	clients map[int]*ClientBasicInfo
+	// channels maps channel ids to channels.
+	channels map[int]*ChannelBasicInfo
+}
+
+// NewState builds a new state mirror, call Run to start syncing.
+func NewState(api *ServerQueryAPI) *State {
+	return &State{
+		api:      api,
+		changes:  make(chan *StateChange, 100),
+		clients:  make(map[int]*ClientBasicInfo),
+		channels: make(map[int]*ChannelBasicInfo),
+	}
+}
+
+// Changes returns the channel of state changes.
+// Changes are dropped if the channel is full. The channel is closed when Run exits.
+func (s *State) Changes() <-chan *StateChange {
+	return s.changes
+}
+
+// DroppedChanges returns the number of changes dropped due to a full buffer.
+func (s *State) DroppedChanges() uint64 {
+	return atomic.LoadUint64(&s.droppedChanges)
+}
+
+// emitChange emits a state change.
+func (s *State) emitChange(change *StateChange) {
+	select {
+	case s.changes <- change:
+	default:
+		atomic.AddUint64(&s.droppedChanges, 1)
+	}
+}
+
+// Run syncs the state and applies events until ctx is canceled or the api Run exits.
+// In supervised mode the state is resynced each time the connection is ready.
+// Run should be called once, after the virtual server has been selected.
+func (s *State) Run(ctx context.Context) error {
+	defer close(s.changes)
+
+	sub := s.api.Subscribe(&SubscriptionConfig{
+		BufferSize: 1000,
+		Overflow:   OverflowDropOldest,
+	})
+	defer sub.Unsubscribe()
+
+	if err := s.Sync(ctx); err != nil {
+		return err
+	}
+
+	var dropped uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case eve, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+
+			// missed events or a reconnect mean the mirror may be stale
+			resync := sub.Dropped() != dropped
+			dropped = sub.Dropped()
+			if sc, ok := eve.(*ConnectionStateChanged); ok && sc.State == ConnectionStateReady {
+				resync = true
+			}
+			// an event that cannot be applied also leaves the mirror stale
+			if !resync && s.applyEvent(eve) != nil {
+				resync = true
+			}
+			if resync {
+				if err := s.Sync(ctx); err != nil && ctx.Err() != nil {
+					return context.Canceled
+				}
+			}
+		}
+	}
+}
+
+// Sync reloads the state from the server.
+func (s *State) Sync(ctx context.Context) error {
+	serverInfo, err := s.api.GetServerInfo(ctx)
+	if err == nil {
+		var channelList []*ChannelListEntry
+		channelList, err = s.api.GetChannelList(ctx)
+		if err == nil {
+			var clientList []*ClientBasicInfo
+			clientList, err = s.api.GetClientList(ctx)
+			if err == nil {
+				s.replace(serverInfo, channelList, clientList)
+			}
+		}
+	}
+
+	s.emitChange(&StateChange{Type: StateResynced, Error: err})
+	return err
+}
+
+// replace replaces the state.
+func (s *State) replace(serverInfo *ServerInfo, channelList []*ChannelListEntry, clientList []*ClientBasicInfo) {
+	channels := make(map[int]*ChannelBasicInfo, len(channelList))
+	for _, ch := range channelList {
+		c := ch.ChannelBasicInfo
+		channels[c.Id] = &c
+	}
+	clients := make(map[int]*ClientBasicInfo, len(clientList))
+	for _, cl := range clientList {
+		c := *cl
+		clients[c.Id] = &c
+	}
+
+	s.mtx.Lock()
+	s.server = serverInfo.ServerBasicInfo
+	s.channels = channels
+	s.clients = clients
+	s.mtx.Unlock()
+}
+
+// applyEvent applies an event to the state.
+// Returns an error without changing the state if the event cannot be decoded.
+func (s *State) applyEvent(eve Event) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var change *StateChange
+	switch e := eve.(type) {
+	case *ClientEnteredView:
+		c := e.ClientBasicInfo
+		c.ChannelId = e.TargetChannel
+		s.clients[c.Id] = &c
+		change = &StateChange{Type: StateClientJoined, ClientId: c.Id, ChannelId: c.ChannelId}
+	case *ClientLeftView:
+		delete(s.clients, e.ClientId)
+		change = &StateChange{Type: StateClientLeft, ClientId: e.ClientId, ChannelId: e.SourceChannel}
+	case *ClientMoved:
+		change = &StateChange{Type: StateClientMoved, ClientId: e.ClientId, ChannelId: e.TargetChannel}
+		if c, ok := s.clients[e.ClientId]; ok {
+			change.PreviousChannelId = c.ChannelId
+			c.ChannelId = e.TargetChannel
+		}
+	case *ChannelCreated:
+		c := e.ChannelBasicInfo
+		c.ParentId = e.ParentChannelId
+		s.insertChannel(&c)
+		change = &StateChange{Type: StateChannelCreated, ChannelId: c.Id}
+	case *ChannelEdited:
+		if c, ok := s.channels[e.Id]; ok {
+			updated := *c
+			if _, err := UnmarshalArguments(encodeArgumentMap(e.Properties), &updated); err != nil {
+				return err
+			}
+			updated.Id, updated.ParentId = c.Id, c.ParentId
+			if updated.Order != c.Order {
+				s.removeChannel(c)
+				s.insertChannel(&updated)
+			} else {
+				*c = updated
+			}
+		}
+		change = &StateChange{Type: StateChannelEdited, ChannelId: e.Id}
+	case *ChannelDeleted:
+		change = &StateChange{Type: StateChannelDeleted, ChannelId: e.ChannelId}
+		if c, ok := s.channels[e.ChannelId]; ok {
+			change.PreviousChannelId = c.ParentId
+			s.deleteChannel(c)
+		}
+	case *ChannelMoved:
+		change = &StateChange{Type: StateChannelMoved, ChannelId: e.ChannelId}
+		if c, ok := s.channels[e.ChannelId]; ok {
+			change.PreviousChannelId = c.ParentId
+			s.removeChannel(c)
+			c.ParentId, c.Order = e.ParentChannelId, e.Order
+			s.insertChannel(c)
+		}
+	case *ServerEdited:
+		updated := s.server
+		if _, err := UnmarshalArguments(encodeArgumentMap(e.Properties), &updated); err != nil {
+			return err
+		}
+		s.server = updated
+		change = &StateChange{Type: StateServerEdited}
+	case *UnknownEvent:
+		// an event that could not be decoded may have changed anything
+		return e.Err
+	}
+
+	if change != nil {
+		s.emitChange(change)
+	}
+	return nil
+}
+
+// insertChannel inserts a channel after the sibling given by its Order.
+// The caller must hold mtx.
+func (s *State) insertChannel(c *ChannelBasicInfo) {
+	for _, sib := range s.channels {
+		if sib.Id != c.Id && sib.ParentId == c.ParentId && sib.Order == c.Order {
+			sib.Order = c.Id
+		}
+	}
+	s.channels[c.Id] = c
+}
+
+// removeChannel unlinks a channel from its siblings and removes it.
+// The caller must hold mtx.
+func (s *State) removeChannel(c *ChannelBasicInfo) {
+	for _, sib := range s.channels {
+		if sib.ParentId == c.ParentId && sib.Order == c.Id {
+			sib.Order = c.Order
+		}
+	}
+	delete(s.channels, c.Id)
+}
+
+// deleteChannel removes a channel and its subchannels.
+// The caller must hold mtx.
+func (s *State) deleteChannel(c *ChannelBasicInfo) {
+	for _, sub := range s.channels {
+		if sub.ParentId == c.Id {
+			s.deleteChannel(sub)
+		}
+	}
+	s.removeChannel(c)
+}
+
+// ServerInfo returns the virtual server properties.
+func (s *State) ServerInfo() *ServerBasicInfo {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	info := s.server
+	return &info
+}
+
+// Client returns a client by client id.
+func (s *State) Client(clientID int) (*ClientBasicInfo, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	c, ok := s.clients[clientID]
+	if !ok {
+		return nil, false
+	}
+	cl := *c
+	return &cl, true
+}
+
+// Clients returns all clients.
+func (s *State) Clients() []*ClientBasicInfo {
+	return s.filterClients(func(c *ClientBasicInfo) bool {
+		return true
+	})
+}
+
+// ClientsByUID returns the clients connected with a unique identifier.
+func (s *State) ClientsByUID(uid string) []*ClientBasicInfo {
+	return s.filterClients(func(c *ClientBasicInfo) bool {
+		return c.UniqueIdentifier == uid
+	})
+}
+
+// ClientsInChannel returns the clients in a channel.
+func (s *State) ClientsInChannel(channelID int) []*ClientBasicInfo {
+	return s.filterClients(func(c *ClientBasicInfo) bool {
+		return c.ChannelId == channelID
+	})
+}
+
+// ChannelOfClient returns the channel id a client is in.
+func (s *State) ChannelOfClient(clientID int) (int, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	c, ok := s.clients[clientID]
+	if !ok {
+		return 0, false
+	}
+	return c.ChannelId, true
+}
+
+// filterClients returns copies of the clients matching the predicate.
+func (s *State) filterClients(pred func(c *ClientBasicInfo) bool) []*ClientBasicInfo {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	var res []*ClientBasicInfo
+	for _, c := range s.clients {
+		if pred(c) {
+			cl := *c
+			res = append(res, &cl)
+		}
+	}
+	return res
+}
+
+// Channel returns a channel by channel id.
+func (s *State) Channel(channelID int) (*ChannelBasicInfo, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	c, ok := s.channels[channelID]
+	if !ok {
+		return nil, false
+	}
+	ch := *c
+	return &ch, true
+}
+
+// Channels returns all channels.
+func (s *State) Channels() []*ChannelBasicInfo {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	res := make([]*ChannelBasicInfo, 0, len(s.channels))
+	for _, c := range s.channels {
+		ch := *c
+		res = append(res, &ch)
+	}
+	return res
+}
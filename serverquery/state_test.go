@@ -0,0 +1,176 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// expectStateChange waits for the next state change and checks its type.
+func expectStateChange(t *testing.T, st *State, changeType StateChangeType) *StateChange {
+	t.Helper()
+	select {
+	case change, ok := <-st.Changes():
+		if !ok {
+			t.Fatal("state changes closed")
+		}
+		if change.Type != changeType {
+			t.Fatalf("expected state change %d, got %#v", changeType, change)
+		}
+		return change
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for state change %d", changeType)
+	}
+	return nil
+}
+
+// expectChannelOrder checks the order field of channels.
+func expectChannelOrder(t *testing.T, st *State, orders map[int]int) {
+	t.Helper()
+	for cid, order := range orders {
+		ch, ok := st.Channel(cid)
+		if !ok {
+			t.Fatalf("expected channel %d to exist", cid)
+		}
+		if ch.Order != order {
+			t.Fatalf("expected channel %d order %d, got %d", cid, order, ch.Order)
+		}
+	}
+}
+
+// TestStateSync tests seeding the state and applying events.
+func TestStateSync(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"serverinfo": {
+			"virtualserver_id=1 virtualserver_name=Test\\sServer virtualserver_port=9987",
+			"error id=0 msg=ok",
+		},
+		"channellist -topic -flags -limits": {
+			"cid=1 pid=0 channel_order=0 channel_name=Lobby|cid=2 pid=0 channel_order=1 channel_name=Games|cid=3 pid=2 channel_order=0 channel_name=Chess",
+			"error id=0 msg=ok",
+		},
+		"clientlist -uid": {
+			"clid=5 cid=1 client_nickname=alice client_unique_identifier=alice=|clid=6 cid=2 client_nickname=bob client_unique_identifier=bob=",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	st := NewState(api)
+	go st.Run(ctx)
+	expectStateChange(t, st, StateResynced)
+
+	if st.ServerInfo().Name != "Test Server" {
+		t.Fatalf("server info not synced: %#v", st.ServerInfo())
+	}
+	if len(st.Clients()) != 2 || len(st.Channels()) != 3 {
+		t.Fatalf("expected 2 clients and 3 channels, got %d and %d", len(st.Clients()), len(st.Channels()))
+	}
+	if cid, _ := st.ChannelOfClient(6); cid != 2 {
+		t.Fatalf("expected client 6 in channel 2, got %d", cid)
+	}
+
+	s.writeLine("notifycliententerview cfid=0 ctid=3 reasonid=0 clid=7 client_unique_identifier=carol= client_nickname=carol client_type=0")
+	change := expectStateChange(t, st, StateClientJoined)
+	if change.ClientId != 7 || change.ChannelId != 3 {
+		t.Fatalf("client joined change incorrect: %#v", change)
+	}
+	if clients := st.ClientsByUID("carol="); len(clients) != 1 || clients[0].Nickname != "carol" {
+		t.Fatalf("expected to find carol by uid: %#v", clients)
+	}
+
+	s.writeLine("notifyclientmoved ctid=1 reasonid=0 clid=7")
+	change = expectStateChange(t, st, StateClientMoved)
+	if change.PreviousChannelId != 3 || change.ChannelId != 1 {
+		t.Fatalf("client moved change incorrect: %#v", change)
+	}
+	if len(st.ClientsInChannel(1)) != 2 {
+		t.Fatalf("expected 2 clients in channel 1: %#v", st.ClientsInChannel(1))
+	}
+
+	s.writeLine("notifyclientleftview cfid=1 ctid=0 reasonid=8 reasonmsg=bye clid=5")
+	expectStateChange(t, st, StateClientLeft)
+	if _, ok := st.Client(5); ok {
+		t.Fatal("expected client 5 to be removed")
+	}
+
+	// a new channel between Lobby and Games
+	s.writeLine("notifychannelcreated cid=4 cpid=0 channel_name=Music channel_order=1 invokerid=1 invokername=serveradmin invokeruid=serveradmin")
+	expectStateChange(t, st, StateChannelCreated)
+	expectChannelOrder(t, st, map[int]int{1: 0, 4: 1, 2: 4})
+
+	s.writeLine("notifychanneledited cid=4 reasonid=10 invokerid=1 invokername=serveradmin invokeruid=serveradmin channel_name=Tunes")
+	expectStateChange(t, st, StateChannelEdited)
+	if ch, _ := st.Channel(4); ch.Name != "Tunes" || ch.Order != 1 {
+		t.Fatalf("channel edit not applied: %#v", ch)
+	}
+
+	s.writeLine("notifychannelmoved cid=4 cpid=2 order=3 reasonid=1 invokerid=1 invokername=serveradmin invokeruid=serveradmin")
+	change = expectStateChange(t, st, StateChannelMoved)
+	if change.PreviousChannelId != 0 {
+		t.Fatalf("channel moved change incorrect: %#v", change)
+	}
+	expectChannelOrder(t, st, map[int]int{2: 1, 3: 0, 4: 3})
+
+	s.writeLine("notifychanneldeleted invokerid=1 invokername=serveradmin invokeruid=serveradmin cid=2")
+	expectStateChange(t, st, StateChannelDeleted)
+	if len(st.Channels()) != 1 {
+		t.Fatalf("expected subchannels to be deleted: %#v", st.Channels())
+	}
+
+	s.writeLine("notifyserveredited reasonid=10 invokerid=1 invokername=serveradmin invokeruid=serveradmin virtualserver_name=Renamed")
+	expectStateChange(t, st, StateServerEdited)
+	if st.ServerInfo().Name != "Renamed" {
+		t.Fatalf("server edit not applied: %#v", st.ServerInfo())
+	}
+}
+
+// TestStateResyncOnBadEdit tests that events that cannot be decoded or applied trigger a resync.
+func TestStateResyncOnBadEdit(t *testing.T) {
+	s, api := newFakeServer(t, map[string][]string{
+		"serverinfo": {
+			"virtualserver_id=1 virtualserver_name=Test\\sServer virtualserver_port=9987",
+			"error id=0 msg=ok",
+		},
+		"channellist -topic -flags -limits": {
+			"cid=1 pid=0 channel_order=0 channel_name=Lobby",
+			"error id=0 msg=ok",
+		},
+		"clientlist -uid": {
+			"clid=5 cid=1 client_nickname=alice client_unique_identifier=alice=",
+			"error id=0 msg=ok",
+		},
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go api.Run(ctx)
+
+	st := NewState(api)
+	go st.Run(ctx)
+	expectStateChange(t, st, StateResynced)
+
+	s.writeLine("notifychanneledited cid=1 reasonid=10 invokerid=1 invokername=serveradmin invokeruid=serveradmin channel_name=Renamed channel_order=first")
+	expectStateChange(t, st, StateResynced)
+	if ch, _ := st.Channel(1); ch.Name != "Lobby" {
+		t.Fatalf("expected the bad edit not to be applied: %#v", ch)
+	}
+
+	s.writeLine("notifyserveredited reasonid=10 invokerid=1 invokername=serveradmin invokeruid=serveradmin virtualserver_name=Renamed virtualserver_default_server_group=guest")
+	expectStateChange(t, st, StateResynced)
+	if st.ServerInfo().Name != "Test Server" {
+		t.Fatalf("expected the bad edit not to be applied: %#v", st.ServerInfo())
+	}
+
+	s.writeLine("notifyclientmoved ctid=lots reasonid=0 clid=5")
+	expectStateChange(t, st, StateResynced)
+	s.expectReceived(t,
+		"serverinfo", "channellist -topic -flags -limits", "clientlist -uid",
+		"serverinfo", "channellist -topic -flags -limits", "clientlist -uid",
+		"serverinfo", "channellist -topic -flags -limits", "clientlist -uid",
+		"serverinfo", "channellist -topic -flags -limits", "clientlist -uid",
+	)
+}
@@ -0,0 +1,198 @@
+package serverquery
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy selects what happens when a subscription buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDropNewest drops the incoming event.
+	OverflowDropNewest OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest buffered event to make room.
+	OverflowDropOldest
+	// OverflowBlock waits up to BlockTimeout for room, then drops the incoming event.
+	// While waiting, no other events or command replies are processed.
+	OverflowBlock
+)
+
+// SubscriptionConfig configures an event subscription.
+type SubscriptionConfig struct {
+	// BufferSize is the size of the event channel buffer.
+	BufferSize int
+	// Overflow is the policy used when the buffer is full.
+	Overflow OverflowPolicy
+	// BlockTimeout is the maximum time to wait with OverflowBlock.
+	BlockTimeout time.Duration
+}
+
+// DefaultSubscriptionConfig is the subscription configuration used when none is given.
+var DefaultSubscriptionConfig = SubscriptionConfig{
+	BufferSize: 10,
+	Overflow:   OverflowDropNewest,
+}
+
+// Subscription is a subscription to the event stream.
+type Subscription struct {
+	// api is the api the subscription belongs to.
+	api *ServerQueryAPI
+	// conf is the subscription config.
+	conf SubscriptionConfig
+	// ch is the event channel.
+	ch chan Event
+	// eventName limits the subscription to an event name, if set.
+	eventName string
+	// filters are the predicates events must match.
+	filters []EventFilter
+	// dropped is the number of dropped events.
+	dropped uint64
+
+	// done is closed when the subscription is closed.
+	done chan struct{}
+	// doneOnce guards closing done.
+	doneOnce sync.Once
+	// mtx guards ch and closed.
+	mtx sync.Mutex
+	// closed is set when ch is closed.
+	closed bool
+}
+
+// Subscribe subscribes to the event stream.
+// The channel is closed when Run exits or Unsubscribe is called.
+// If conf is nil, DefaultSubscriptionConfig is used.
+func (a *ServerQueryAPI) Subscribe(conf *SubscriptionConfig, filters ...EventFilter) *Subscription {
+	return a.subscribe(conf, "", filters)
+}
+
+// subscribe builds and registers a subscription, optionally limited to an event name.
+func (a *ServerQueryAPI) subscribe(conf *SubscriptionConfig, eventName string, filters []EventFilter) *Subscription {
+	if conf == nil {
+		conf = &DefaultSubscriptionConfig
+	}
+	sub := &Subscription{
+		api:       a,
+		conf:      *conf,
+		ch:        make(chan Event, conf.BufferSize),
+		eventName: eventName,
+		filters:   filters,
+		done:      make(chan struct{}),
+	}
+	a.subscriptionsMtx.Lock()
+	a.subscriptions = append(a.subscriptions, sub)
+	a.subscriptionsMtx.Unlock()
+	return sub
+}
+
+// closeSubscriptions closes and removes all subscriptions.
+func (a *ServerQueryAPI) closeSubscriptions() {
+	a.subscriptionsMtx.Lock()
+	subs := a.subscriptions
+	a.subscriptions = nil
+	a.subscriptionsMtx.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+}
+
+// Events returns the event channel.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns the number of events dropped due to a full buffer.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Unsubscribe removes the subscription and closes the event channel.
+func (s *Subscription) Unsubscribe() {
+	a := s.api
+	a.subscriptionsMtx.Lock()
+	for i, sub := range a.subscriptions {
+		if sub == s {
+			a.subscriptions = append(a.subscriptions[:i], a.subscriptions[i+1:]...)
+			break
+		}
+	}
+	a.subscriptionsMtx.Unlock()
+	s.close()
+}
+
+// close closes the event channel.
+func (s *Subscription) close() {
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// matches checks if the event matches the event name and filters.
+func (s *Subscription) matches(eve Event) bool {
+	if s.eventName != "" && s.eventName != eve.GetEventName() {
+		return false
+	}
+	for _, filter := range s.filters {
+		if !filter(eve) {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver delivers an event according to the overflow policy.
+func (s *Subscription) deliver(ctx context.Context, eve Event) {
+	if !s.matches(eve) {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- eve:
+		return
+	default:
+	}
+
+	switch s.conf.Overflow {
+	case OverflowDropOldest:
+		// an unbuffered channel has nothing to drop
+		for cap(s.ch) != 0 {
+			select {
+			case <-s.ch:
+				atomic.AddUint64(&s.dropped, 1)
+			default:
+			}
+			select {
+			case s.ch <- eve:
+				return
+			default:
+			}
+		}
+	case OverflowBlock:
+		timer := time.NewTimer(s.conf.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case s.ch <- eve:
+			return
+		case <-timer.C:
+		case <-s.done:
+		case <-ctx.Done():
+		}
+	}
+
+	atomic.AddUint64(&s.dropped, 1)
+}
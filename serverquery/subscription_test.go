@@ -0,0 +1,124 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// textEvent builds a text message event for tests.
+func textEvent(msg string) Event {
+	return &TextMessageReceived{Message: msg}
+}
+
+// drainMessages reads the buffered text messages of a subscription.
+func drainMessages(sub *Subscription) []string {
+	var msgs []string
+	for {
+		select {
+		case eve := <-sub.Events():
+			msgs = append(msgs, eve.(*TextMessageReceived).Message)
+		default:
+			return msgs
+		}
+	}
+}
+
+// TestSubscriptionOverflow tests the overflow policies.
+func TestSubscriptionOverflow(t *testing.T) {
+	ctx := context.Background()
+	api := NewServerQueryAPI(nil)
+	newest := api.Subscribe(&SubscriptionConfig{BufferSize: 2, Overflow: OverflowDropNewest})
+	oldest := api.Subscribe(&SubscriptionConfig{BufferSize: 2, Overflow: OverflowDropOldest})
+	block := api.Subscribe(&SubscriptionConfig{
+		BufferSize:   2,
+		Overflow:     OverflowBlock,
+		BlockTimeout: 10 * time.Millisecond,
+	})
+
+	for _, msg := range []string{"a", "b", "c"} {
+		api.emitEvent(ctx, textEvent(msg))
+	}
+
+	if msgs := drainMessages(newest); len(msgs) != 2 || msgs[0] != "a" || msgs[1] != "b" {
+		t.Fatalf("drop newest: unexpected messages %v", msgs)
+	}
+	if msgs := drainMessages(oldest); len(msgs) != 2 || msgs[0] != "b" || msgs[1] != "c" {
+		t.Fatalf("drop oldest: unexpected messages %v", msgs)
+	}
+	if msgs := drainMessages(block); len(msgs) != 2 || msgs[0] != "a" || msgs[1] != "b" {
+		t.Fatalf("block: unexpected messages %v", msgs)
+	}
+	for _, sub := range []*Subscription{newest, oldest, block} {
+		if sub.Dropped() != 1 {
+			t.Fatalf("expected 1 dropped event, got %d", sub.Dropped())
+		}
+	}
+
+	// a blocked delivery completes once the consumer catches up
+	api.emitEvent(ctx, textEvent("d"))
+	api.emitEvent(ctx, textEvent("e"))
+	go func() {
+		time.Sleep(time.Millisecond)
+		<-block.Events()
+	}()
+	block.conf.BlockTimeout = time.Second
+	api.emitEvent(ctx, textEvent("f"))
+	if msgs := drainMessages(block); len(msgs) != 2 || msgs[1] != "f" {
+		t.Fatalf("block: unexpected messages %v", msgs)
+	}
+	if block.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped event, got %d", block.Dropped())
+	}
+}
+
+// TestSubscriptionUnsubscribe tests closing a subscription.
+func TestSubscriptionUnsubscribe(t *testing.T) {
+	api := NewServerQueryAPI(nil)
+	sub := api.Subscribe(nil)
+	other := api.Subscribe(nil)
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("expected unsubscribed channel to be closed")
+	}
+	api.emitEvent(context.Background(), textEvent("a"))
+	if msgs := drainMessages(other); len(msgs) != 1 {
+		t.Fatalf("expected remaining subscription to get the event, got %v", msgs)
+	}
+}
+
+// TestSubscriptionLifecycle tests that subscriptions close only when Run exits.
+func TestSubscriptionLifecycle(t *testing.T) {
+	_, api := newFakeServer(t, map[string][]string{
+		"whoami": {"error id=0 msg=ok"},
+	})
+	events := api.Events()
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- api.Run(ctx)
+	}()
+
+	if _, err := api.ExecuteCommand(ctx, &rawCommand{name: "whoami"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	select {
+	case <-events:
+		t.Fatal("expected events channel to stay open while Run is running")
+	default:
+	}
+
+	ctxCancel()
+	<-errCh
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("expected events channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected events channel to be closed after Run exits")
+	}
+}
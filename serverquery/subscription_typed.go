@@ -0,0 +1,135 @@
+package serverquery
+
+import (
+	"reflect"
+)
+
+// EventFilter is a predicate events must match to be delivered to a subscription.
+type EventFilter func(eve Event) bool
+
+// targetModeEvent is an event with a text message target mode.
+type targetModeEvent interface {
+	// GetTargetMode returns the target mode.
+	GetTargetMode() int
+}
+
+// invokerEvent is an event caused by an invoker.
+type invokerEvent interface {
+	// GetInvokerUID returns the unique identifier of the invoker.
+	GetInvokerUID() string
+}
+
+// channelEvent is an event related to a channel.
+type channelEvent interface {
+	// GetChannelID returns the id of the channel.
+	GetChannelID() int
+}
+
+// FilterTargetMode matches events with the target mode.
+// 1 = user, 2 = channel, 3 = server
+func FilterTargetMode(targetMode int) EventFilter {
+	return func(eve Event) bool {
+		e, ok := eve.(targetModeEvent)
+		return ok && e.GetTargetMode() == targetMode
+	}
+}
+
+// FilterInvokerUID matches events caused by the invoker unique identifier.
+func FilterInvokerUID(uid string) EventFilter {
+	return func(eve Event) bool {
+		e, ok := eve.(invokerEvent)
+		return ok && e.GetInvokerUID() == uid
+	}
+}
+
+// FilterChannelID matches events related to the channel id.
+func FilterChannelID(channelID int) EventFilter {
+	return func(eve Event) bool {
+		e, ok := eve.(channelEvent)
+		return ok && e.GetChannelID() == channelID
+	}
+}
+
+// FilterFunc converts a typed predicate to an EventFilter.
+// Events of other types do not match.
+func FilterFunc[T Event](pred func(eve T) bool) EventFilter {
+	return func(eve Event) bool {
+		e, ok := eve.(T)
+		return ok && pred(e)
+	}
+}
+
+// TypedSubscription is a subscription to events of a single type.
+type TypedSubscription[T Event] struct {
+	*Subscription
+
+	// ch is the typed event channel.
+	ch chan T
+}
+
+// Subscribe subscribes to events of type T that match all filters, for example:
+//
+//	Subscribe[*TextMessageReceived](api, FilterTargetMode(1))
+//
+// Events are matched by the name returned from GetEventName, the same name
+// used by the event constructor table, so other event types are skipped
+// without being delivered to the subscription.
+func Subscribe[T Event](api *ServerQueryAPI, filters ...EventFilter) *TypedSubscription[T] {
+	return SubscribeConfig[T](api, nil, filters...)
+}
+
+// SubscribeConfig is Subscribe with a subscription config.
+// If conf is nil, DefaultSubscriptionConfig is used.
+func SubscribeConfig[T Event](api *ServerQueryAPI, conf *SubscriptionConfig, filters ...EventFilter) *TypedSubscription[T] {
+	var eventName string
+	// interface types have no name and match by type assertion only
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() != reflect.Interface {
+		// GetEventName may read the receiver, so never call it on a nil pointer
+		proto := reflect.New(t).Elem()
+		if t.Kind() == reflect.Ptr {
+			proto = reflect.New(t.Elem())
+		}
+		eventName = proto.Interface().(Event).GetEventName()
+	}
+
+	sub := &TypedSubscription[T]{
+		Subscription: api.subscribe(conf, eventName, filters),
+		ch:           make(chan T),
+	}
+	go sub.forward()
+	return sub
+}
+
+// HandleEvents calls handler for each event of type T that matches all filters.
+// The handler is called from a single goroutine until the subscription is closed.
+func HandleEvents[T Event](api *ServerQueryAPI, handler func(eve T), filters ...EventFilter) *TypedSubscription[T] {
+	sub := Subscribe[T](api, filters...)
+	go func() {
+		for eve := range sub.Events() {
+			handler(eve)
+		}
+	}()
+	return sub
+}
+
+// Events returns the typed event channel.
+// The channel is closed when Run exits or Unsubscribe is called.
+func (s *TypedSubscription[T]) Events() <-chan T {
+	return s.ch
+}
+
+// forward forwards events to the typed channel.
+func (s *TypedSubscription[T]) forward() {
+	defer close(s.ch)
+	for eve := range s.Subscription.Events() {
+		e, ok := eve.(T)
+		if !ok {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		case <-s.done:
+			return
+		}
+	}
+}
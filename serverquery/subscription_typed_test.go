@@ -0,0 +1,102 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestTypedSubscription tests typed subscriptions with filters.
+func TestTypedSubscription(t *testing.T) {
+	ctx := context.Background()
+	api := NewServerQueryAPI(nil)
+	private := Subscribe[*TextMessageReceived](api, FilterTargetMode(1), FilterInvokerUID("abc="))
+	entered := Subscribe[*ClientEnteredView](api, FilterChannelID(5))
+	long := Subscribe[*TextMessageReceived](api, FilterFunc(func(e *TextMessageReceived) bool {
+		return len(e.Message) > 3
+	}))
+	handled := make(chan *ClientLeftView, 10)
+	HandleEvents(api, func(e *ClientLeftView) {
+		handled <- e
+	})
+
+	for _, eve := range []Event{
+		&TextMessageReceived{TargetMode: 2, InvokerUID: "abc=", Message: "chan"},
+		&TextMessageReceived{TargetMode: 1, InvokerUID: "def=", Message: "other"},
+		&TextMessageReceived{TargetMode: 1, InvokerUID: "abc=", Message: "hi"},
+		&ClientEnteredView{ChannelMovement: ChannelMovement{TargetChannel: 4}},
+		&ClientEnteredView{ChannelMovement: ChannelMovement{TargetChannel: 5}},
+		&ClientLeftView{ClientId: 9},
+	} {
+		api.emitEvent(ctx, eve)
+	}
+
+	select {
+	case e := <-private.Events():
+		if e.Message != "hi" {
+			t.Fatalf("unexpected private message: %#v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for private message")
+	}
+	select {
+	case e := <-entered.Events():
+		if e.TargetChannel != 5 {
+			t.Fatalf("unexpected entered view: %#v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for entered view")
+	}
+	for _, expected := range []string{"chan", "other"} {
+		select {
+		case e := <-long.Events():
+			if e.Message != expected {
+				t.Fatalf("expected message %q, got %q", expected, e.Message)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for message %q", expected)
+		}
+	}
+	select {
+	case e := <-handled:
+		if e.ClientId != 9 {
+			t.Fatalf("unexpected left view: %#v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for handler")
+	}
+
+	private.Unsubscribe()
+	select {
+	case _, ok := <-private.Events():
+		if ok {
+			t.Fatal("expected no more events after unsubscribe")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected typed channel to close after unsubscribe")
+	}
+}
+
+// receiverEvent is an event that reads its receiver to build the event name.
+type receiverEvent struct {
+	// Prefix is prepended to the event name.
+	Prefix string
+}
+
+// GetEventName returns the event name.
+func (e *receiverEvent) GetEventName() string {
+	return e.Prefix + "notifyreceiver"
+}
+
+// TestTypedSubscriptionReceiverName tests subscribing to an event whose name reads the receiver.
+func TestTypedSubscriptionReceiverName(t *testing.T) {
+	api := NewServerQueryAPI(nil)
+	sub := Subscribe[*receiverEvent](api)
+	api.emitEvent(context.Background(), &receiverEvent{})
+
+	select {
+	case <-sub.Events():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
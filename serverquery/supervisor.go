@@ -0,0 +1,260 @@
+package serverquery
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Dialer opens a new connection to the server.
+// The returned read-writer must have consumed the server introduction.
+type Dialer func(ctx context.Context) (*ServerQueryReadWriter, error)
+
+// ReconnectConfig configures the reconnect backoff in supervised mode.
+type ReconnectConfig struct {
+	// MinBackoff is the delay before the first reconnect attempt.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between reconnect attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each failed attempt.
+	Multiplier float64
+	// Jitter is the fraction of the delay to randomize, between 0 and 1.
+	Jitter float64
+}
+
+// DefaultReconnectConfig is the reconnect configuration used when none is given.
+var DefaultReconnectConfig = ReconnectConfig{
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// backoff returns the delay before the given reconnect attempt, starting at 0.
+func (c *ReconnectConfig) backoff(attempt int) time.Duration {
+	delay := float64(c.MinBackoff) * math.Pow(c.Multiplier, float64(attempt))
+	if max := float64(c.MaxBackoff); delay > max {
+		delay = max
+	}
+	delay += delay * c.Jitter * (rand.Float64()*2 - 1)
+	return time.Duration(delay)
+}
+
+// NewSupervisedServerQueryAPI builds a client that connects with dialer when Run is called.
+// When the connection drops, Run reconnects and replays the session setup:
+// the login, the selected virtual server, the nickname and the notify registrations.
+// If conf is nil, DefaultReconnectConfig is used.
+func NewSupervisedServerQueryAPI(dialer Dialer, conf *ReconnectConfig) *ServerQueryAPI {
+	a := NewServerQueryAPI(nil)
+	a.dialer = dialer
+	if conf != nil {
+		a.reconnectConf = *conf
+	} else {
+		a.reconnectConf = DefaultReconnectConfig
+	}
+	return a
+}
+
+// DialSupervised builds a supervised client for the telnet API at endp.
+// The connection is established when Run is called.
+func DialSupervised(endp string, conf *ReconnectConfig) *ServerQueryAPI {
+	return NewSupervisedServerQueryAPI(func(ctx context.Context) (*ServerQueryReadWriter, error) {
+		return dialReadWriter(ctx, endp)
+	}, conf)
+}
+
+// ConnectionState is the state of a supervised connection.
+type ConnectionState int
+
+const (
+	// ConnectionStateConnecting indicates a connection attempt is in progress.
+	ConnectionStateConnecting ConnectionState = iota
+	// ConnectionStateReady indicates the connection is up and the session has been restored.
+	ConnectionStateReady
+	// ConnectionStateDisconnected indicates the connection was lost or could not be established.
+	ConnectionStateDisconnected
+)
+
+// String returns the name of the state.
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateConnecting:
+		return "connecting"
+	case ConnectionStateReady:
+		return "ready"
+	case ConnectionStateDisconnected:
+		return "disconnected"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateChanged is emitted by a supervised client when the connection state changes.
+type ConnectionStateChanged struct {
+	// State is the new state.
+	State ConnectionState
+	// Attempt is the number of consecutive connection attempts since the connection was last ready.
+	Attempt int
+	// Error is the reason for the disconnect, if any.
+	Error error
+}
+
+// GetEventName returns the event name.
+func (e *ConnectionStateChanged) GetEventName() string {
+	return "connectionstate"
+}
+
+// notifyRegistration is a recorded servernotifyregister call.
+type notifyRegistration struct {
+	eventType string
+	id        int
+}
+
+// sessionState records the session setup of a client.
+type sessionState struct {
+	mtx sync.Mutex
+
+	username      string
+	password      string
+	serverPort    int
+	serverID      int
+	nickname      string
+	notifications []notifyRegistration
+}
+
+// setLogin records the login credentials.
+func (s *sessionState) setLogin(username, password string) {
+	s.mtx.Lock()
+	s.username, s.password = username, password
+	s.mtx.Unlock()
+}
+
+// setServerPort records the virtual server selected by port.
+func (s *sessionState) setServerPort(port int) {
+	s.mtx.Lock()
+	s.serverPort, s.serverID = port, 0
+	// notify registrations are per virtual server
+	s.notifications = nil
+	s.mtx.Unlock()
+}
+
+// setServerID records the virtual server selected by id.
+func (s *sessionState) setServerID(sid int) {
+	s.mtx.Lock()
+	s.serverPort, s.serverID = 0, sid
+	s.notifications = nil
+	s.mtx.Unlock()
+}
+
+// setNickname records the nickname.
+func (s *sessionState) setNickname(nickname string) {
+	s.mtx.Lock()
+	s.nickname = nickname
+	s.mtx.Unlock()
+}
+
+// addNotification records a notify registration.
+func (s *sessionState) addNotification(eventType string, id int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	reg := notifyRegistration{eventType: eventType, id: id}
+	for _, n := range s.notifications {
+		if n == reg {
+			return
+		}
+	}
+	s.notifications = append(s.notifications, reg)
+}
+
+// buildCommands returns the commands to replay the session.
+func (s *sessionState) buildCommands() []Command {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var cmds []Command
+	if s.username != "" {
+		cmds = append(cmds, &LoginCommand{username: s.username, password: s.password})
+	}
+	if s.serverPort != 0 {
+		cmds = append(cmds, &UseCommand{Port: s.serverPort})
+	}
+	if s.serverID != 0 {
+		cmds = append(cmds, &UseServerIDCommand{ServerID: s.serverID})
+	}
+	if s.nickname != "" {
+		cmds = append(cmds, &ClientUpdateCommand{Nickname: s.nickname})
+	}
+	for _, n := range s.notifications {
+		cmds = append(cmds, buildServerNotifyRegisterCommand(n.eventType, n.id))
+	}
+	return cmds
+}
+
+// replaySession replays the recorded session setup on the current connection.
+func (a *ServerQueryAPI) replaySession(ctx context.Context) error {
+	for _, cmd := range a.session.buildCommands() {
+		mc, err := MarshalCommand(cmd)
+		if err != nil {
+			return err
+		}
+		if _, err := a.executePending(ctx, &pendingCommand{ctx: ctx, command: mc}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runSupervised connects, runs and reconnects until ctx is canceled or Close is called.
+func (a *ServerQueryAPI) runSupervised(ctx context.Context) error {
+	var attempt int
+	for {
+		attempt++
+		a.emitEvent(ctx, &ConnectionStateChanged{State: ConnectionStateConnecting, Attempt: attempt})
+		rw, err := a.dialer(ctx)
+		if err == nil {
+			if !a.setReadWriter(rw) {
+				rw.Close()
+				return context.Canceled
+			}
+			err = a.runConnection(ctx, rw, func(ctx context.Context) error {
+				if err := a.replaySession(ctx); err != nil {
+					return err
+				}
+				a.emitEvent(ctx, &ConnectionStateChanged{State: ConnectionStateReady, Attempt: attempt})
+				attempt = 0
+				return nil
+			})
+			rw.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		default:
+		}
+		a.connMtx.Lock()
+		closed := a.closed
+		a.connMtx.Unlock()
+		if closed {
+			return context.Canceled
+		}
+
+		a.emitEvent(ctx, &ConnectionStateChanged{
+			State:   ConnectionStateDisconnected,
+			Attempt: attempt,
+			Error:   err,
+		})
+
+		backoffAttempt := attempt - 1
+		if backoffAttempt < 0 {
+			backoffAttempt = 0
+		}
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case <-time.After(a.reconnectConf.backoff(backoffAttempt)):
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package serverquery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// expectConnectionState waits for a connection state event.
+func expectConnectionState(t *testing.T, events <-chan Event, state ConnectionState) {
+	t.Helper()
+	for {
+		select {
+		case eve, ok := <-events:
+			if !ok {
+				t.Fatalf("event channel closed waiting for state %v", state)
+			}
+			sc, ok := eve.(*ConnectionStateChanged)
+			if !ok {
+				continue
+			}
+			if sc.State != state {
+				t.Fatalf("expected state %v, got %v (%v)", state, sc.State, sc.Error)
+			}
+			return
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for state %v", state)
+		}
+	}
+}
+
+// TestSupervisedReconnect tests reconnecting and replaying the session setup.
+func TestSupervisedReconnect(t *testing.T) {
+	ok := []string{"error id=0 msg=ok"}
+	replies := map[string][]string{
+		"login serveradmin secret":          ok,
+		"use port=9987":                     ok,
+		"clientupdate client_nickname=bot":  ok,
+		"servernotifyregister event=server": ok,
+	}
+	servers := make(chan *fakeServer, 10)
+	api := NewSupervisedServerQueryAPI(func(ctx context.Context) (*ServerQueryReadWriter, error) {
+		s, clientConn := startFakeServer(t, replies)
+		servers <- s
+		return NewServerQueryReadWriter(clientConn), nil
+	}, &ReconnectConfig{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+		Multiplier: 2,
+	})
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	events := api.Events()
+	go api.Run(ctx)
+
+	expectConnectionState(t, events, ConnectionStateConnecting)
+	expectConnectionState(t, events, ConnectionStateReady)
+
+	if err := api.Login(ctx, "serveradmin", "secret"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := api.UseServer(ctx, 9987); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := api.SetNickname(ctx, "bot"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := api.ServerNotifyRegister(ctx, "server", 0); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	first := <-servers
+	first.conn.Close()
+	expectConnectionState(t, events, ConnectionStateDisconnected)
+	expectConnectionState(t, events, ConnectionStateConnecting)
+	expectConnectionState(t, events, ConnectionStateReady)
+
+	second := <-servers
+	second.expectReceived(
+		t,
+		"login serveradmin secret",
+		"use port=9987",
+		"clientupdate client_nickname=bot",
+		"servernotifyregister event=server",
+	)
+}
+
+// TestReconnectBackoff tests the backoff grows and is capped.
+func TestReconnectBackoff(t *testing.T) {
+	conf := &ReconnectConfig{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+		Multiplier: 2,
+		Jitter:     0.5,
+	}
+	for attempt, base := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		d := conf.backoff(attempt)
+		if d < base/2 || d > base*3/2 {
+			t.Fatalf("attempt %d: backoff %v out of range of %v", attempt, d, base)
+		}
+	}
+}
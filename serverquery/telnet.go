@@ -8,7 +8,9 @@ import (
 	"regexp"
 )
 
-var invalidCharRegex = regexp.MustCompile(`[^ -~]+`)
+// invalidCharRegex matches control characters, such as the trailing \r.
+// Escaped arguments never contain raw control characters.
+var invalidCharRegex = regexp.MustCompile(`[\x00-\x1f\x7f]+`)
 
 // ServerQueryReadWriter can talk to the API
 type ServerQueryReadWriter struct {
@@ -33,6 +35,17 @@ func (rw *ServerQueryReadWriter) WriteCommand(command string) error {
 	return err
 }
 
+// waitForServerIntro waits for the two introduction lines from the server.
+func (rw *ServerQueryReadWriter) waitForServerIntro() error {
+	ignoreLines := 2
+	for i := 0; i < ignoreLines; i++ {
+		if _, err := rw.ReadCommand(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReadCommand reads a full command in.
 func (rw *ServerQueryReadWriter) ReadCommand() (rstr string, rerr error) {
 	for {
@@ -46,6 +59,10 @@ func (rw *ServerQueryReadWriter) ReadCommand() (rstr string, rerr error) {
 
 		resultString := rw.scanner.Text()
 		resultString = invalidCharRegex.ReplaceAllString(resultString, "")
+		// blank lines carry nothing and would shift the intro and reply lines
+		if len(resultString) == 0 {
+			continue
+		}
 		return resultString, nil
 	}
 }
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"unicode"
 
-	"github.com/kballard/go-shellquote"
 	"github.com/pkg/errors"
 )
 
@@ -16,76 +15,85 @@ func ParseArgumentValue(val string) (interface{}, error) {
 		return nil, errors.New("cannot have empty argument value")
 	}
 
-	valRunes := []rune(val)
-	firstRune := valRunes[0]
-	if firstRune == '"' {
-		return string(valRunes[1 : len(valRunes)-1]), nil
-	}
-	if unicode.IsDigit(firstRune) && !strings.Contains(val, " ") {
+	numVal := strings.TrimPrefix(val, "-")
+	if len(numVal) != 0 && unicode.IsDigit([]rune(numVal)[0]) && !strings.Contains(val, " ") {
 		numbers := strings.Split(val, ",")
 		decCount := strings.Count(val, ".")
 		isFloat := decCount > 0
 		var elementType reflect.Type
 		if isFloat {
-			elementType = reflect.TypeOf(float32(0))
+			elementType = reflect.TypeOf(float64(0))
 		} else {
 			elementType = reflect.TypeOf(int(0))
 		}
 
-		parseElement := func(e string) reflect.Value {
+		parseElement := func(e string) (reflect.Value, error) {
 			if isFloat {
-				i, _ := strconv.ParseFloat(e, 32)
-				return reflect.ValueOf(float32(i))
+				i, err := strconv.ParseFloat(e, 64)
+				return reflect.ValueOf(i), err
 			}
 
-			i, _ := strconv.ParseInt(e, 10, 32)
-			return reflect.ValueOf(int(i))
-		}
-
-		if len(numbers) == 1 {
-			return parseElement(numbers[0]).Interface(), nil
+			i, err := strconv.ParseInt(e, 10, 64)
+			return reflect.ValueOf(int(i)), err
 		}
 
 		arr := reflect.MakeSlice(
 			reflect.SliceOf(elementType),
-			0, 0,
+			0, len(numbers),
 		)
 		for _, numStr := range numbers {
-			ele := parseElement(numStr)
+			ele, err := parseElement(numStr)
+			if err != nil {
+				// numeric-looking text like an ip address or an out of range number
+				return val, nil
+			}
 			arr = reflect.Append(arr, ele)
 		}
+		if len(numbers) == 1 {
+			return arr.Index(0).Interface(), nil
+		}
 		return arr.Interface(), nil
 	}
 
 	return val, nil
 }
 
-// ParseArgumentList parses an args string to a map.
-func ParseArgumentList(args string) (map[string]interface{}, error) {
-	args = strings.Replace(args, "\\s", "&nbsp;", -1)
-	parts, err := shellquote.Split(args)
-	if err != nil {
-		return nil, err
-	}
-
-	res := make(map[string]interface{})
-	for _, pt := range parts {
-		pt = strings.Replace(pt, "&nbsp;", " ", -1)
+// splitArgumentList splits an args string into unescaped key/value pairs.
+// Flags without a value (like -uid) map to an empty string.
+func splitArgumentList(args string) map[string]string {
+	res := make(map[string]string)
+	for _, pt := range strings.Fields(args) {
 		ptEqParts := strings.SplitN(pt, "=", 2)
 		key := ptEqParts[0]
 		if len(key) < 1 {
-			return nil, nil
+			continue
 		}
 
 		if len(ptEqParts) > 1 {
-			ptVal, err := ParseArgumentValue(ptEqParts[1])
-			if err != nil {
-				return nil, err
-			}
-			res[key] = ptVal
+			res[key] = UnescapeString(ptEqParts[1])
 		} else {
+			res[key] = ""
+		}
+	}
+
+	return res
+}
+
+// ParseArgumentList parses an args string to a map.
+func ParseArgumentList(args string) (map[string]interface{}, error) {
+	rawMap := splitArgumentList(args)
+	res := make(map[string]interface{}, len(rawMap))
+	for key, rawVal := range rawMap {
+		if len(rawVal) == 0 {
 			res[key] = nil
+			continue
+		}
+
+		ptVal, err := ParseArgumentValue(rawVal)
+		if err != nil {
+			return nil, err
 		}
+		res[key] = ptVal
 	}
 
 	return res, nil
@@ -106,10 +114,7 @@ func unmarshalObject(str []rune, outp interface{}) error {
 	}
 	outpVal = outpVal.Elem()
 
-	argMap, err := ParseArgumentList(string(str))
-	if err != nil {
-		return err
-	}
+	rawMap := splitArgumentList(string(str))
 
 	for i := 0; i < outpType.NumField(); i++ {
 		fieldInfo := outpType.Field(i)
@@ -129,7 +134,9 @@ func unmarshalObject(str []rune, outp interface{}) error {
 			if fieldType.Elem().Kind() != reflect.Struct {
 				continue
 			}
-			unmarshalObject(str, outpField.Interface())
+			if err := unmarshalObject(str, outpField.Interface()); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -137,19 +144,38 @@ func unmarshalObject(str []rune, outp interface{}) error {
 		if !ok {
 			continue
 		}
-		argVal, ok := argMap[sqtag]
+		sqtag, _ = parseTag(sqtag)
+		rawVal, ok := rawMap[sqtag]
 		if !ok {
 			continue
 		}
-		delete(argMap, sqtag)
-		if argVal == nil {
+
+		// strings are taken verbatim so numeric-looking text stays intact
+		ot := outpField.Type()
+		if ot.Kind() == reflect.String {
+			outpField.SetString(rawVal)
+			continue
+		}
+		if len(rawVal) == 0 {
 			continue
 		}
 
-		// fmt.Printf("set: %s -> %#v\n", outpField.String(), argVal)
+		argVal, err := ParseArgumentValue(rawVal)
+		if err != nil {
+			return err
+		}
+
 		v := reflect.ValueOf(argVal)
 		t := reflect.TypeOf(argVal)
-		ot := outpField.Type()
+
+		// a value that did not parse as a number cannot fill a numeric field
+		elemKind := ot.Kind()
+		if elemKind == reflect.Slice {
+			elemKind = ot.Elem().Kind()
+		}
+		if t.Kind() == reflect.String && elemKind != reflect.String {
+			return errors.Errorf("invalid value for %s: %q", sqtag, rawVal)
+		}
 
 		if ot.Kind() == reflect.Slice && t.Kind() != reflect.Slice {
 			sval := reflect.MakeSlice(ot, 0, 1)
@@ -175,15 +201,29 @@ func unmarshalObject(str []rune, outp interface{}) error {
 		outpField.Set(v)
 	}
 
-	/*
-		for unhandled := range argMap {
-			fmt.Printf("unhandled argument: %s\n", unhandled)
-		}
-	*/
-
 	return nil
 }
 
+// splitRepeatedKeys splits an entry where a key repeats into separate entries.
+// Some commands, like banclient, reply with one entry per line instead of a pipe-separated list,
+// and reply lines are joined with spaces.
+func splitRepeatedKeys(part string) []string {
+	var entries []string
+	var fields []string
+	seen := make(map[string]struct{})
+	for _, field := range strings.Fields(part) {
+		key := strings.SplitN(field, "=", 2)[0]
+		if _, ok := seen[key]; ok {
+			entries = append(entries, strings.Join(fields, " "))
+			fields = nil
+			seen = make(map[string]struct{})
+		}
+		seen[key] = struct{}{}
+		fields = append(fields, field)
+	}
+	return append(entries, strings.Join(fields, " "))
+}
+
 // unmarshalArray unmarshals an encoded array into an output array.
 func unmarshalArray(str []rune, outp interface{}) (interface{}, error) {
 	outpType := reflect.TypeOf(outp)
@@ -205,7 +245,15 @@ func unmarshalArray(str []rune, outp interface{}) (interface{}, error) {
 	}
 
 	outpVal := reflect.ValueOf(outp)
-	pts := strings.Split(string(str), "|")
+	// an empty result is an empty list, not a list with one empty entry
+	if len(strings.TrimSpace(string(str))) == 0 {
+		return outpVal.Interface(), nil
+	}
+
+	var pts []string
+	for _, part := range strings.Split(string(str), "|") {
+		pts = append(pts, splitRepeatedKeys(part)...)
+	}
 	for _, part := range pts {
 		elemVal := reflect.New(elemType)
 
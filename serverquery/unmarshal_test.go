@@ -6,7 +6,7 @@ import (
 )
 
 func TestParseArgumentList(t *testing.T) {
-	res, err := ParseArgumentList("test=\"hello world\" type=2 test2=hello\\sthere")
+	res, err := ParseArgumentList("test=hello\\sworld type=-2 test2=hello\\sthere\\p\\/")
 	if err != nil {
 		panic(err)
 	}
@@ -17,19 +17,19 @@ func TestParseArgumentList(t *testing.T) {
 	}
 
 	val = res["test2"]
-	if valStr, ok := val.(string); !ok || valStr != "hello there" {
+	if valStr, ok := val.(string); !ok || valStr != "hello there|/" {
 		t.Fatalf("test2 parsed incorrectly: %#v", val)
 	}
 
 	valb := res["type"]
-	if valb != 2 {
-		t.Fatalf("type parsed incorrectly: %#v (%v) != %#v", valb, reflect.TypeOf(valb).Kind(), 2)
+	if valb != -2 {
+		t.Fatalf("type parsed incorrectly: %#v (%v) != %#v", valb, reflect.TypeOf(valb).Kind(), -2)
 	}
 }
 
 func TestParseObjectList(t *testing.T) {
 	outp := make([]*TestArgument, 0)
-	res, err := UnmarshalArguments("thingname=\"hello world\" thingtype=2 nested=\"nested thing\"|thingname=\"goodbye\" thingtype=3", outp)
+	res, err := UnmarshalArguments("thingname=hello\\sworld thingtype=2 nested=nested\\sthing|thingname=goodbye thingtype=3", outp)
 	if err != nil {
 		panic(err)
 	}
@@ -78,3 +78,46 @@ func TestE2E(t *testing.T) {
 		t.Fatalf("e2e mismatch: %#v != %#v", arg, outpb)
 	}
 }
+
+// TestUnmarshalEmptyList tests an empty result decodes to an empty list.
+func TestUnmarshalEmptyList(t *testing.T) {
+	res, err := UnmarshalArguments(" ", make([]*TestArgument, 0))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if l := len(res.([]*TestArgument)); l != 0 {
+		t.Fatalf("expected empty list, got %d entries", l)
+	}
+}
+
+// TestUnmarshalNumbers tests decoding large numbers and rejecting invalid ones.
+func TestUnmarshalNumbers(t *testing.T) {
+	info := &ClientDBInfo{}
+	if _, err := UnmarshalArguments("client_total_bytes_downloaded=5000000000 client_lastip=10.0.0.1", info); err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.TotalBytesDownloaded != 5000000000 {
+		t.Fatalf("expected 5000000000 bytes, got %d", info.TotalBytesDownloaded)
+	}
+
+	for _, args := range []string{
+		"client_total_bytes_downloaded=99999999999999999999",
+		"client_total_bytes_downloaded=1.2.3",
+		"client_total_bytes_downloaded=lots",
+	} {
+		if _, err := UnmarshalArguments(args, &ClientDBInfo{}); err == nil {
+			t.Fatalf("expected %q to fail", args)
+		}
+	}
+
+	// invalid values in embedded structs fail as well
+	embedded := &struct{ ClientDBInfo }{}
+	if _, err := UnmarshalArguments("client_total_bytes_downloaded=lots", embedded); err == nil {
+		t.Fatal("expected invalid value in embedded struct to fail")
+	}
+
+	val, err := ParseArgumentValue("10.0.0.1")
+	if err != nil || val != "10.0.0.1" {
+		t.Fatalf("expected ip address to stay a string, got %#v (%v)", val, err)
+	}
+}
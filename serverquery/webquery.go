@@ -0,0 +1,174 @@
+package serverquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WebQueryClient executes commands with the WebQuery HTTP API (TeamSpeak 3.12+).
+type WebQueryClient struct {
+	// Commands provides the typed command helpers.
+	Commands
+
+	// baseURL is the base URL of the API, like http://localhost:10080
+	baseURL string
+	// apiKey is the API key sent with each request.
+	apiKey string
+	// serverID is the virtual server id, or 0 for instance-level commands.
+	serverID int
+	// httpClient is the http client.
+	httpClient *http.Client
+}
+
+// _ is a type assertion
+var _ CommandExecutor = ((*WebQueryClient)(nil))
+
+// NewWebQueryClient builds a new WebQuery client.
+// serverID selects the virtual server, or 0 for instance-level commands.
+// If httpClient is nil, http.DefaultClient is used.
+func NewWebQueryClient(baseURL, apiKey string, serverID int, httpClient *http.Client) *WebQueryClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c := &WebQueryClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		serverID:   serverID,
+		httpClient: httpClient,
+	}
+	c.Commands.CommandExecutor = c
+	return c
+}
+
+// webQueryStatus is the status section of a WebQuery response.
+type webQueryStatus struct {
+	// Code is the error id.
+	Code int `json:"code"`
+	// Message is the error message.
+	Message string `json:"message"`
+	// ExtraMessage is the additional error message.
+	ExtraMessage string `json:"extra_message"`
+	// FailedPermID is the id of the permission that caused the error.
+	FailedPermID json.Number `json:"failed_permid"`
+}
+
+// webQueryResponse is the envelope of a WebQuery response.
+type webQueryResponse struct {
+	// Body contains the result entries.
+	Body []map[string]interface{} `json:"body"`
+	// Status is the result status.
+	Status webQueryStatus `json:"status"`
+}
+
+// buildRequestURL converts a marshalled command to a WebQuery URL.
+func (c *WebQueryClient) buildRequestURL(marshalled string) string {
+	var pathBuf bytes.Buffer
+	pathBuf.WriteString(c.baseURL)
+	pathBuf.WriteRune('/')
+	if c.serverID != 0 {
+		pathBuf.WriteString(strconv.Itoa(c.serverID))
+		pathBuf.WriteRune('/')
+	}
+
+	var query []string
+	for i, pt := range strings.Fields(marshalled) {
+		if i == 0 {
+			pathBuf.WriteString(url.PathEscape(pt))
+			continue
+		}
+		for _, group := range strings.Split(pt, "|") {
+			ptEqParts := strings.SplitN(group, "=", 2)
+			key := url.QueryEscape(ptEqParts[0])
+			if len(ptEqParts) == 1 {
+				query = append(query, key)
+				continue
+			}
+			query = append(query, key+"="+url.QueryEscape(UnescapeString(ptEqParts[1])))
+		}
+	}
+
+	if len(query) != 0 {
+		pathBuf.WriteRune('?')
+		pathBuf.WriteString(strings.Join(query, "&"))
+	}
+	return pathBuf.String()
+}
+
+// encodeBody converts a WebQuery response body to ServerQuery syntax.
+func encodeBody(body []map[string]interface{}) string {
+	entries := make([]string, len(body))
+	for i, entry := range body {
+		args := make(map[string]string, len(entry))
+		for key, val := range entry {
+			switch v := val.(type) {
+			case nil:
+			case string:
+				args[key] = v
+			default:
+				args[key] = fmt.Sprint(v)
+			}
+		}
+		entries[i] = encodeArgumentMap(args)
+	}
+	return strings.Join(entries, "|")
+}
+
+// ExecuteCommand executes a command with a HTTP request, waiting for the result.
+func (c *WebQueryClient) ExecuteCommand(
+	ctx context.Context,
+	command Command,
+) (interface{}, error) {
+	mc, err := MarshalCommand(command)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.buildRequestURL(mc), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	respObj := &webQueryResponse{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(respObj); err != nil {
+		return nil, errors.Wrapf(err, "decode webquery response (http status %d)", resp.StatusCode)
+	}
+
+	failedPermID, _ := respObj.Status.FailedPermID.Int64()
+	if err := newServerError(&callResult{
+		ErrorId:      respObj.Status.Code,
+		ErrorMessage: respObj.Status.Message,
+		ExtraMessage: respObj.Status.ExtraMessage,
+		FailedPermID: int(failedPermID),
+	}); err != nil {
+		return nil, err
+	}
+
+	resultObj := command.GetResponseType()
+	if resultObj == nil || len(respObj.Body) == 0 {
+		return resultObj, nil
+	}
+	return UnmarshalArguments(encodeBody(respObj.Body), resultObj)
+}
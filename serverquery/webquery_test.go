@@ -0,0 +1,110 @@
+package serverquery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// newFakeWebQuery starts a fake WebQuery server with handlers by path.
+func newFakeWebQuery(t *testing.T, handlers map[string]func(r *http.Request) string) *WebQueryClient {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "secret" {
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write([]byte(`{"status":{"code":5122,"message":"invalid apikey"}}`))
+			return
+		}
+		handler, ok := handlers[r.URL.Path]
+		if !ok {
+			rw.Write([]byte(`{"status":{"code":256,"message":"command not found"}}`))
+			return
+		}
+		rw.Write([]byte(handler(r)))
+	}))
+	t.Cleanup(srv.Close)
+	return NewWebQueryClient(srv.URL, "secret", 1, srv.Client())
+}
+
+// TestWebQueryClientList tests decoding a list response.
+func TestWebQueryClientList(t *testing.T) {
+	client := newFakeWebQuery(t, map[string]func(r *http.Request) string{
+		"/1/clientlist": func(r *http.Request) string {
+			if r.URL.RawQuery != "-uid" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			return `{"body":[` +
+				`{"clid":"1","client_database_id":"3","client_nickname":"alice","client_type":"0","client_unique_identifier":"abc="},` +
+				`{"clid":"2","client_database_id":"4","client_nickname":"bob | the builder","client_type":"1"}` +
+				`],"status":{"code":0,"message":"ok"}}`
+		},
+	})
+
+	res, err := client.ExecuteCommand(context.Background(), &GetClientListCommand{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	clients := res.([]*ClientBasicInfo)
+	if len(clients) != 2 {
+		t.Fatalf("expected 2 clients, got %d", len(clients))
+	}
+	if clients[0].Id != 1 || clients[0].UniqueIdentifier != "abc=" || clients[0].DatabaseId != 3 {
+		t.Fatalf("client decoded incorrectly: %#v", clients[0])
+	}
+	if clients[1].Nickname != "bob | the builder" || clients[1].Type != 1 {
+		t.Fatalf("client decoded incorrectly: %#v", clients[1])
+	}
+}
+
+// TestWebQueryArguments tests encoding command arguments.
+func TestWebQueryArguments(t *testing.T) {
+	client := newFakeWebQuery(t, map[string]func(r *http.Request) string{
+		"/1/sendtextmessage": func(r *http.Request) string {
+			q := r.URL.Query()
+			if q.Get("targetmode") != "2" || q.Get("target") != "5" || q.Get("msg") != "hello world & co/ltd" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			return `{"status":{"code":0,"message":"ok"}}`
+		},
+		"/1/clientinfo": func(r *http.Request) string {
+			if r.URL.Query().Get("clid") != "7" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			return `{"body":[{"client_nickname":"carol","client_servergroups":"6,8"}],"status":{"code":0,"message":"ok"}}`
+		},
+	})
+
+	ctx := context.Background()
+	if _, err := client.ExecuteCommand(ctx, &SendTextMessageCommand{
+		TargetMode: 2,
+		Target:     5,
+		Message:    "hello world & co/ltd",
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// the typed helpers work with the WebQuery transport
+	info, err := client.GetClientInfo(ctx, 7)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.Id != 7 || info.Nickname != "carol" || len(info.ServerGroups) != 2 || info.ServerGroups[1] != 8 {
+		t.Fatalf("client info decoded incorrectly: %#v", info)
+	}
+}
+
+// TestWebQueryError tests decoding an error status.
+func TestWebQueryError(t *testing.T) {
+	client := newFakeWebQuery(t, map[string]func(r *http.Request) string{
+		"/1/clientinfo": func(r *http.Request) string {
+			return `{"status":{"code":512,"message":"invalid clientID"}}`
+		},
+	})
+
+	_, err := client.ExecuteCommand(context.Background(), &GetClientInfoCommand{ClientId: 7})
+	if !errors.Is(err, ErrInvalidClientID) {
+		t.Fatalf("expected invalid client id error, got: %v", err)
+	}
+}